@@ -0,0 +1,121 @@
+package di
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// assignableItem returns the default item of a registered type that is assignable to t.
+// If several registered types are assignable, the item registered first is used.
+func (c *container) assignableItem(t reflect.Type) (*containerItem, bool) {
+	var candidate *containerItem
+	for _, group := range c.groups {
+		if group.t == t || !group.t.AssignableTo(t) {
+			continue
+		}
+		item, err := c.defaultItem(group, group.t)
+		if err != nil {
+			continue
+		}
+		if candidate == nil || item.option.index < candidate.option.index {
+			candidate = item
+		}
+	}
+	if candidate == nil && c.parent != nil {
+		return c.parent.assignableItem(t)
+	}
+	return candidate, candidate != nil
+}
+
+// pointerReceiverError explains why a registered value type does not satisfy the interface t when
+// a pointer to the value type would. It returns nil if no registered type is in that situation.
+func (c *container) pointerReceiverError(t reflect.Type) error {
+	if t.Kind() != reflect.Interface {
+		return nil
+	}
+	for _, group := range c.groups {
+		if group.t.Kind() == reflect.Pointer || group.t.Kind() == reflect.Interface {
+			continue
+		}
+		if !reflect.PointerTo(group.t).Implements(t) {
+			continue
+		}
+		for i := 0; i < t.NumMethod(); i++ {
+			method := t.Method(i)
+			if _, ok := group.t.MethodByName(method.Name); ok {
+				continue
+			}
+			return fmt.Errorf(
+				"%w: '%s' does not implement '%s' because method '%s' has a pointer receiver, register '*%s' instead",
+				ErrNotExist, group.t, t, method.Name, group.t)
+		}
+	}
+	return nil
+}
+
+// assignableItems returns every item whose type is t or is assignable to t in registration order.
+// Types registered in a scope hide the registrations of the same type in its parents.
+func (c *container) assignableItems(t reflect.Type) []*containerItem {
+	var items []*containerItem
+	for _, group := range c.visibleGroups() {
+		if !group.t.AssignableTo(t) {
+			continue
+		}
+		items = append(items, group.ordered()...)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].option.index < items[j].option.index
+	})
+	return items
+}
+
+func (c *container) ResolveBest(t reflect.Type, score func(any) int) (any, error) {
+	var (
+		best      any
+		bestScore int
+		found     bool
+	)
+	for _, item := range c.assignableItems(t) {
+		instance, err := item.resolve(c, nil)
+		if err != nil {
+			return nil, c.check(err)
+		}
+		s := score(instance)
+		if !found || s > bestScore {
+			best, bestScore, found = instance, s, true
+		}
+	}
+	if !found {
+		return nil, c.check(fmt.Errorf("%w: no registration is assignable to '%s'", ErrNotExist, t))
+	}
+	return best, nil
+}
+
+func (c *container) ResolveMostSpecific(t reflect.Type) (any, error) {
+	var (
+		best      *containerItem
+		bestScore int
+	)
+	for _, item := range c.assignableItems(t) {
+		s := specificity(item.option.typ)
+		if best == nil || s > bestScore {
+			best, bestScore = item, s
+		}
+	}
+	if best == nil {
+		return nil, c.check(fmt.Errorf("%w: no registration is assignable to '%s'", ErrNotExist, t))
+	}
+	instance, err := best.resolve(c, nil)
+	return instance, c.check(err)
+}
+
+// specificity scores how narrow a registered type is. Interfaces score their method count
+// and concrete types score higher than any interface.
+func specificity(t reflect.Type) int {
+	if t.Kind() != reflect.Interface {
+		return math.MaxInt
+	}
+	return t.NumMethod()
+}
@@ -0,0 +1,178 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type Named struct {
+	name string
+}
+
+func (n Named) Name() string {
+	return n.name
+}
+
+// Embedded satisfies SampleInterface only through the promoted Name method of Named
+type Embedded struct {
+	Named
+	Value int
+}
+
+var EmbeddedType = reflect.TypeOf((*Embedded)(nil)).Elem()
+
+// DerivedSample narrows SampleInterface by embedding it
+type DerivedSample interface {
+	SampleInterface
+	Priority() int
+}
+
+type derivedSample struct {
+	Named
+}
+
+func (d derivedSample) Priority() int {
+	return 1
+}
+
+var DerivedSampleType = reflect.TypeOf((*DerivedSample)(nil)).Elem()
+
+func TestAssignable(t *testing.T) {
+	t.Run("pointer implements interface", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "test"})
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+	})
+	t.Run("embedded", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "embedded"}, Value: 1})
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		sample, ok := instance.(SampleInterface)
+		require.True(t, ok)
+		require.Equal(t, "embedded", sample.Name())
+
+		embedded, ok := instance.(Embedded)
+		require.True(t, ok)
+		require.Equal(t, 1, embedded.Value)
+	})
+	t.Run("embedded pointer", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.PointerTo(EmbeddedType), &Embedded{Named: Named{name: "embedded"}})
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "embedded", instance.(SampleInterface).Name())
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "embedded"}})
+
+		_, err := container.Resolve(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("not assignable", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(StringType, "test")
+
+		_, err := container.Resolve(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("pointer receiver", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.TypeOf(SampleStruct{}), SampleStruct{name: "test"})
+
+		_, err := container.Resolve(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), "method 'Name' has a pointer receiver")
+		require.Contains(t, err.Error(), "register '*di_test.SampleStruct' instead")
+	})
+	t.Run("exact type wins", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "assignable"})
+		container.RegisterInstance(SampleInterfaceType, NewSample("exact"))
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "exact", instance.(SampleInterface).Name())
+	})
+	t.Run("first assignable registration wins", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "first"})
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "second"}})
+		container.RegisterInstance(reflect.PointerTo(EmbeddedType), &Embedded{Named: Named{name: "third"}})
+
+		// groups are stored in a map so repeat to catch any dependence on iteration order
+		for i := 0; i < 20; i++ {
+			instance, err := container.Resolve(SampleInterfaceType)
+			require.NoError(t, err)
+			require.Equal(t, "first", instance.(SampleInterface).Name())
+		}
+	})
+	t.Run("resolve best", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("b"))
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "ccc"}})
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "aa"})
+		container.RegisterInstance(StringType, "not a sample")
+
+		score := func(instance any) int {
+			return len(instance.(SampleInterface).Name())
+		}
+		instance, err := container.ResolveBest(SampleInterfaceType, score)
+		require.NoError(t, err)
+		require.Equal(t, "ccc", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve best tie", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "first"})
+		container.RegisterInstance(SampleInterfaceType, NewSample("second"))
+		instance, err := container.ResolveBest(SampleInterfaceType, func(any) int { return 0 })
+		require.NoError(t, err)
+		require.Equal(t, "first", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve best none", func(t *testing.T) {
+		container := di.NewContainer()
+		_, err := container.ResolveBest(SampleInterfaceType, func(any) int { return 0 })
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("resolve most specific", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("base one"))
+		container.RegisterInstance(DerivedSampleType, derivedSample{Named{name: "derived"}})
+		container.RegisterInstance(SampleInterfaceType, NewSample("base two"), di.WithName("two"))
+		container.RegisterInstance(StringType, "not a sample")
+
+		instance, err := container.ResolveMostSpecific(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "derived", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve most specific prefers concrete", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DerivedSampleType, derivedSample{Named{name: "derived"}})
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "concrete"}})
+		instance, err := container.ResolveMostSpecific(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "concrete", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve most specific tie", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("first"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("second"), di.WithName("second"))
+		instance, err := container.ResolveMostSpecific(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "first", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve most specific none", func(t *testing.T) {
+		container := di.NewContainer()
+		_, err := container.ResolveMostSpecific(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
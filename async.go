@@ -0,0 +1,98 @@
+package di
+
+import (
+	"reflect"
+)
+
+var errorChanType = reflect.TypeOf((<-chan error)(nil))
+
+// isAsyncConstructor returns true if the function type t returns a value and a channel that reports
+// the result of its background initialization
+func isAsyncConstructor(t reflect.Type) bool {
+	return t.NumOut() == 2 && t.Out(1) == errorChanType
+}
+
+// asyncStatus holds the result of the background initialization of an instance
+type asyncStatus struct {
+	done chan struct{}
+	err  error
+}
+
+// newAsyncStatus waits for the first error or the close of the channel in the background.
+// A nil channel reports success immediately.
+func newAsyncStatus(errs <-chan error) *asyncStatus {
+	status := &asyncStatus{
+		done: make(chan struct{}),
+	}
+	if errs == nil {
+		close(status.done)
+		return status
+	}
+	go func() {
+		status.err = <-errs
+		close(status.done)
+	}()
+	return status
+}
+
+// report returns a channel that receives the result of the initialization, nil on success, and is then closed
+func (s *asyncStatus) report() <-chan error {
+	errs := make(chan error, 1)
+	if s == nil {
+		errs <- nil
+		close(errs)
+		return errs
+	}
+	go func() {
+		<-s.done
+		errs <- s.err
+		close(errs)
+	}()
+	return errs
+}
+
+// asyncDelegate calls the async constructor and records the status of its initialization on the resolution
+// that built the instance so it is returned with the instance rather than shared by concurrent resolutions
+func asyncDelegate(constructor any, o *invokeOptions) FuncResolver {
+	t := reflect.TypeOf(constructor)
+	return func(r Resolver) (any, error) {
+		parameters, err := resolveParameters(r, t, o)
+		if err != nil {
+			return nil, invokeError(r, t, err)
+		}
+		results := reflect.ValueOf(constructor).Call(parameters)
+		errs := results[1].Interface().(<-chan error)
+		if current, ok := r.(*resolution); ok {
+			current.async = newAsyncStatus(errs)
+		}
+		return results[0].Interface(), nil
+	}
+}
+
+// reportAsync passes the status of the instance resolved directly under the resolution to ResolveAsync
+func (r *resolution) reportAsync(status *asyncStatus) {
+	if r != nil && r.asyncResult != nil {
+		*r.asyncResult = status
+	}
+}
+
+func (c *container) ResolveAsync(t reflect.Type) (any, <-chan error, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, nil, c.check(err)
+	}
+	item, err := c.defaultItem(group, t)
+	if err != nil {
+		return nil, nil, c.check(err)
+	}
+	var status *asyncStatus
+	parent := &resolution{
+		container:   c,
+		asyncResult: &status,
+	}
+	instance, err := item.resolve(c, parent)
+	if err != nil {
+		return nil, nil, c.check(err)
+	}
+	return instance, status.report(), nil
+}
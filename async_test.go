@@ -0,0 +1,157 @@
+package di_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAsync(t *testing.T) {
+	t.Run("reports success", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		started := make(chan error)
+		err := container.RegisterConstructor(func(name string) (SampleInterface, <-chan error) {
+			return NewSample(name), started
+		})
+		require.NoError(t, err)
+
+		instance, errs, err := container.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+
+		close(started)
+		require.NoError(t, <-errs)
+		_, ok := <-errs
+		require.False(t, ok)
+	})
+	t.Run("reports failure", func(t *testing.T) {
+		container := di.NewContainer()
+		expected := errors.New("expected")
+		started := make(chan error, 1)
+		err := container.RegisterConstructor(func() (SampleInterface, <-chan error) {
+			return NewSample("test"), started
+		})
+		require.NoError(t, err)
+
+		instance, errs, err := container.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+
+		started <- expected
+		require.ErrorIs(t, <-errs, expected)
+
+		// the static instance reports the same result to later resolutions
+		_, errs, err = container.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		require.ErrorIs(t, <-errs, expected)
+
+		instance, err = container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+	})
+	t.Run("per request", func(t *testing.T) {
+		container := di.NewContainer()
+		var (
+			mutex sync.Mutex
+			count int
+		)
+		err := container.RegisterConstructor(func() (SampleInterface, <-chan error) {
+			mutex.Lock()
+			count++
+			name := strconv.Itoa(count)
+			mutex.Unlock()
+
+			// each instance reports its own name as the initialization error
+			errs := make(chan error, 1)
+			errs <- errors.New(name)
+			return NewSample(name), errs
+		}, di.WithLifetime(di.LifetimePerRequest))
+		require.NoError(t, err)
+
+		const routines = 20
+		names := make([]string, routines)
+		reported := make([]error, routines)
+		errs := make([]error, routines)
+		var wg sync.WaitGroup
+		for i := 0; i < routines; i++ {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				instance, status, err := container.ResolveAsync(SampleInterfaceType)
+				errs[index] = err
+				if err != nil {
+					return
+				}
+				names[index] = instance.(SampleInterface).Name()
+				reported[index] = <-status
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < routines; i++ {
+			require.NoError(t, errs[i])
+			require.EqualError(t, reported[i], names[i])
+		}
+	})
+	t.Run("scoped", func(t *testing.T) {
+		container := di.NewContainer()
+		failed := errors.New("failed")
+		statuses := []chan error{make(chan error), make(chan error, 1)}
+		statuses[1] <- failed
+		count := 0
+		err := container.RegisterConstructor(func() (SampleInterface, <-chan error) {
+			errs := statuses[count]
+			count++
+			return NewSample(strconv.Itoa(count)), errs
+		}, di.WithLifetime(di.LifetimeScoped))
+		require.NoError(t, err)
+
+		left := container.Scope()
+		_, _, err = left.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		right := container.Scope()
+		_, errs, err := right.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		require.ErrorIs(t, <-errs, failed)
+
+		// the instance of the left scope is still initializing
+		_, errs, err = left.ResolveAsync(SampleInterfaceType)
+		require.NoError(t, err)
+		select {
+		case err := <-errs:
+			require.Fail(t, "unexpected initialization result", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	})
+	t.Run("optional collections", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func(dependencies []DependencyInterface) (AggregateInterface, <-chan error) {
+			return NewAggregate(dependencies), nil
+		}, di.WithOptionalCollections())
+		require.NoError(t, err)
+
+		instance, errs, err := container.ResolveAsync(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(instance.(AggregateInterface).Names()))
+		require.NoError(t, <-errs)
+	})
+	t.Run("synchronous registration", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		instance, errs, err := container.ResolveAsync(StringType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance)
+		require.NoError(t, <-errs)
+	})
+	t.Run("missing", func(t *testing.T) {
+		container := di.NewContainer()
+		_, _, err := container.ResolveAsync(StringType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
@@ -0,0 +1,27 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// canAutowire returns true if the type is a struct or a pointer to a struct
+func canAutowire(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// autowireType allocates the struct type and injects its fields
+func (c *container) autowireType(parent *resolution, t reflect.Type) (any, error) {
+	if parent.autowiring(t) {
+		return nil, fmt.Errorf("%w: '%s'", ErrCircular, t.String())
+	}
+	r := &resolution{
+		container: c,
+		parent:    parent,
+		autowired: t,
+	}
+	return newInjected(r, t)
+}
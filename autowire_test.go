@@ -0,0 +1,52 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type AutowireFirst struct {
+	Second *AutowireSecond `inject:""`
+}
+
+type AutowireSecond struct {
+	First *AutowireFirst `inject:""`
+}
+
+func TestAutowire(t *testing.T) {
+	t.Run("two level", func(t *testing.T) {
+		container := di.NewContainer(di.WithAutowire())
+		container.RegisterInstance(InjectedType, &injected{})
+
+		called := false
+		_, err := di.Invoke(container, func(wrapper *Wrapper, parent Parent) {
+			called = true
+			require.NotNil(t, wrapper)
+			require.NotNil(t, wrapper.Injected)
+			require.Nil(t, wrapper.NotInjected)
+		})
+		require.NoError(t, err)
+		require.True(t, called)
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(InjectedType, &injected{})
+
+		_, err := di.Invoke(container, func(wrapper *Wrapper) {})
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("missing leaf", func(t *testing.T) {
+		container := di.NewContainer(di.WithAutowire())
+
+		_, err := di.Invoke(container, func(wrapper *Wrapper) {})
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("circular", func(t *testing.T) {
+		container := di.NewContainer(di.WithAutowire())
+
+		_, err := di.Invoke(container, func(first *AutowireFirst) {})
+		require.ErrorIs(t, err, di.ErrCircular)
+	})
+}
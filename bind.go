@@ -0,0 +1,52 @@
+//go:build go1.18
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Binding binds the Iface type to an implementation in the container.
+// Go does not allow type parameters on methods so binding to an implementation type uses the To function.
+type Binding[Iface any] struct {
+	container Container
+}
+
+// Bind starts a binding of the Iface type in the container
+func Bind[Iface any](container Container) *Binding[Iface] {
+	return &Binding[Iface]{
+		container: container,
+	}
+}
+
+// ToInstance binds the Iface type to the given instance
+func (b *Binding[Iface]) ToInstance(instance Iface, options ...InstanceRegistrationOption) {
+	RegisterInstance(b.container, instance, options...)
+}
+
+// ToConstructor binds the Iface type to the given constructor.
+// The return type of the constructor must be assignable to Iface.
+func (b *Binding[Iface]) ToConstructor(constructor any, options ...InstanceRegistrationOption) error {
+	t := reflect.TypeOf((*Iface)(nil)).Elem()
+	options = append([]InstanceRegistrationOption{WithRegisterAs(t)}, options...)
+	return b.container.RegisterConstructor(constructor, options...)
+}
+
+// To binds the Iface type of the binding to the Impl type. Resolving Iface resolves Impl from the container.
+func To[Impl any, Iface any](b *Binding[Iface], options ...InstanceRegistrationOption) error {
+	implType := reflect.TypeOf((*Impl)(nil)).Elem()
+	ifaceType := reflect.TypeOf((*Iface)(nil)).Elem()
+	if !implType.AssignableTo(ifaceType) {
+		return fmt.Errorf("type '%s' is not assignable to '%s'", implType, ifaceType)
+	}
+	RegisterDynamic(b.container, func(r Resolver) (Iface, error) {
+		var zero Iface
+		impl, err := Resolve[Impl](r)
+		if err != nil {
+			return zero, err
+		}
+		return cast[Iface](ifaceType, impl)
+	}, options...)
+	return nil
+}
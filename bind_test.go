@@ -0,0 +1,49 @@
+//go:build go1.18
+
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBind(t *testing.T) {
+	t.Run("to", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() *SampleStruct {
+			return &SampleStruct{name: "test"}
+		}))
+		err := di.To[*SampleStruct](di.Bind[SampleInterface](container))
+		require.NoError(t, err)
+
+		instance, err := di.Resolve[SampleInterface](container)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.Name())
+	})
+	t.Run("to not assignable", func(t *testing.T) {
+		container := di.NewContainer()
+		err := di.To[*SampleStruct](di.Bind[Runner](container))
+		require.Error(t, err)
+	})
+	t.Run("to instance", func(t *testing.T) {
+		container := di.NewContainer()
+		di.Bind[SampleInterface](container).ToInstance(&SampleStruct{name: "test"})
+
+		instance, err := di.Resolve[SampleInterface](container)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.Name())
+	})
+	t.Run("to constructor", func(t *testing.T) {
+		container := di.NewContainer()
+		err := di.Bind[SampleInterface](container).ToConstructor(func() *SampleStruct {
+			return &SampleStruct{name: "test"}
+		}, di.WithName("test"))
+		require.NoError(t, err)
+
+		instance, err := di.ResolveByName[SampleInterface](container, "test")
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.Name())
+	})
+}
@@ -0,0 +1,103 @@
+package di
+
+import (
+	"time"
+)
+
+// CacheEntry is an instance cached by a registration with a cache strategy
+type CacheEntry struct {
+	Instance any
+
+	// Created is the time the instance was constructed
+	Created time.Time
+
+	// Hits is the number of times the instance was returned from the cache
+	Hits int
+}
+
+// CacheStrategy decides if a registration caches the instances it constructs and for how long.
+// The container keeps one entry per registration and constructs a new instance when the strategy rejects it.
+type CacheStrategy interface {
+	// Cache returns true if the constructed instance should be cached
+	Cache(instance any) bool
+
+	// Valid returns true if the cached entry can be returned instead of constructing a new instance
+	Valid(entry CacheEntry) bool
+}
+
+// lifetimeCacheStrategy is the cache strategy of a built-in lifetime. The container caches its instances with the
+// lifetime's own cache so statics construct exactly once and scoped instances are cached per scope.
+type lifetimeCacheStrategy struct {
+	lifetime Lifetime
+}
+
+func (s lifetimeCacheStrategy) Cache(any) bool        { return s.lifetime != LifetimePerRequest }
+func (s lifetimeCacheStrategy) Valid(CacheEntry) bool { return s.lifetime != LifetimePerRequest }
+
+var (
+	// StaticCacheStrategy caches the first instance forever, it is the strategy of LifetimeStatic
+	StaticCacheStrategy CacheStrategy = lifetimeCacheStrategy{lifetime: LifetimeStatic}
+
+	// PerRequestCacheStrategy never caches, it is the strategy of LifetimePerRequest
+	PerRequestCacheStrategy CacheStrategy = lifetimeCacheStrategy{lifetime: LifetimePerRequest}
+)
+
+// WithCacheStrategy caches the instances of the registration with the strategy instead of its lifetime.
+// The strategy of a built-in lifetime sets the lifetime of the registration.
+// An instance rejected by the strategy is finalized and closed before a new instance is constructed,
+// an error doing so is returned from the resolution.
+func WithCacheStrategy(strategy CacheStrategy) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		if lifetime, ok := strategy.(lifetimeCacheStrategy); ok {
+			i.lifetime = lifetime.lifetime
+			i.cacheStrategy = nil
+			return
+		}
+		i.cacheStrategy = strategy
+	}
+}
+
+// strategy returns the cache strategy of the item, the strategy of its lifetime if none was set
+func (i *containerItem) strategy() CacheStrategy {
+	if i.option.cacheStrategy != nil {
+		return i.option.cacheStrategy
+	}
+	return lifetimeCacheStrategy{lifetime: i.option.lifetime}
+}
+
+// resolveStrategy returns the cached instance if the strategy accepts it, otherwise it constructs a new instance
+// and caches it if the strategy allows
+func (i *containerItem) resolveStrategy(c *container, parent *resolution) (any, bool, error) {
+	i.cacheMutex.Lock()
+	defer i.cacheMutex.Unlock()
+
+	strategy := i.option.cacheStrategy
+	if i.cacheEntry != nil {
+		if strategy.Valid(*i.cacheEntry) {
+			i.cacheEntry.Hits++
+			parent.reportAsync(i.async)
+			return i.cacheEntry.Instance, true, nil
+		}
+		i.cacheEntry = nil
+		i.async = nil
+
+		// the rejected instance is disposed now instead of staying referenced until the container is closed
+		if err := c.root().dispose(i); err != nil {
+			return nil, false, err
+		}
+	}
+	data, status, err := i.construct(c, parent)
+	if err != nil {
+		return nil, false, err
+	}
+	if strategy.Cache(data) {
+		i.cacheEntry = &CacheEntry{
+			Instance: data,
+			Created:  time.Now(),
+		}
+		i.async = status
+		c.root().track(i, data)
+	}
+	parent.reportAsync(status)
+	return data, false, nil
+}
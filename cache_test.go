@@ -0,0 +1,102 @@
+package di_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type ttlStrategy struct {
+	ttl time.Duration
+	now func() time.Time
+}
+
+func (s ttlStrategy) Cache(any) bool { return true }
+
+func (s ttlStrategy) Valid(entry di.CacheEntry) bool {
+	return s.now().Sub(entry.Created) < s.ttl
+}
+
+type countStrategy struct {
+	uses int
+}
+
+func (s countStrategy) Cache(any) bool { return true }
+
+func (s countStrategy) Valid(entry di.CacheEntry) bool {
+	return entry.Hits+1 < s.uses
+}
+
+func TestCacheStrategy(t *testing.T) {
+	register := func(strategy di.CacheStrategy, options ...di.InstanceRegistrationOption) (di.Container, *int) {
+		constructed := 0
+		container := di.NewContainer()
+		options = append(options, di.WithCacheStrategy(strategy))
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			constructed++
+			return NewSample("test"), nil
+		}, options...)
+		return container, &constructed
+	}
+	resolve := func(t *testing.T, container di.Container, times int) {
+		for i := 0; i < times; i++ {
+			_, err := container.Resolve(SampleInterfaceType)
+			require.NoError(t, err)
+		}
+	}
+	t.Run("ttl", func(t *testing.T) {
+		offset := time.Duration(0)
+		container, constructed := register(ttlStrategy{
+			ttl: time.Minute,
+			now: func() time.Time { return time.Now().Add(offset) },
+		})
+		resolve(t, container, 3)
+		require.Equal(t, 1, *constructed)
+
+		offset = 2 * time.Minute
+		resolve(t, container, 1)
+		require.Equal(t, 2, *constructed)
+	})
+	t.Run("count", func(t *testing.T) {
+		container, constructed := register(countStrategy{uses: 2})
+		resolve(t, container, 5)
+		require.Equal(t, 3, *constructed)
+	})
+	t.Run("clear cache", func(t *testing.T) {
+		container, constructed := register(countStrategy{uses: 10})
+		resolve(t, container, 3)
+		require.Equal(t, 1, *constructed)
+
+		container.ClearCache()
+		resolve(t, container, 1)
+		require.Equal(t, 2, *constructed)
+	})
+	t.Run("disposes rejected", func(t *testing.T) {
+		finalized := 0
+		container, constructed := register(countStrategy{uses: 1}, di.WithFinalizer(func(any) error {
+			finalized++
+			return nil
+		}))
+		resolve(t, container, 5)
+		require.Equal(t, 5, *constructed)
+		require.Equal(t, 4, finalized)
+		require.Len(t, container.ConstructedInstances(), 1)
+
+		require.NoError(t, container.Close())
+		require.Equal(t, 5, finalized)
+	})
+	t.Run("static", func(t *testing.T) {
+		container, constructed := register(di.StaticCacheStrategy, di.WithLifetime(di.LifetimePerRequest))
+		resolve(t, container, 3)
+		require.Equal(t, 1, *constructed)
+		require.Equal(t, di.LifetimeStatic, container.Registrations(SampleInterfaceType)[0].Lifetime)
+	})
+	t.Run("per request", func(t *testing.T) {
+		container, constructed := register(di.PerRequestCacheStrategy)
+		resolve(t, container, 3)
+		require.Equal(t, 3, *constructed)
+		require.Equal(t, di.LifetimePerRequest, container.Registrations(SampleInterfaceType)[0].Lifetime)
+	})
+}
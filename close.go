@@ -0,0 +1,135 @@
+package di
+
+import (
+	"io"
+)
+
+// DisposeOrder is the order Close disposes constructed instances in
+type DisposeOrder int
+
+const (
+	// DisposeLIFO disposes instances in reverse construction order
+	DisposeLIFO DisposeOrder = 0
+	// DisposeFIFO disposes instances in construction order
+	DisposeFIFO DisposeOrder = 1
+)
+
+// WithDisposeOrder sets the order Close disposes constructed instances in. The default is DisposeLIFO.
+func WithDisposeOrder(order DisposeOrder) ContainerOption {
+	return containerOption(func(c *container) {
+		c.disposeOrder = order
+	})
+}
+
+// constructedInstance is an instance constructed from an item that is closed with the container
+type constructedInstance struct {
+	item *containerItem
+	data any
+}
+
+// track records the instance constructed from the item so it can be closed with the container
+func (c *container) track(item *containerItem, data any) {
+	// the registration that constructed a shared instance tracks it
+	if item.option.shared {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.constructed = append(c.constructed, constructedInstance{
+		item: item,
+		data: data,
+	})
+}
+
+// untrack removes the instances constructed from the item from the instances closed with the container and returns them
+func (c *container) untrack(item *containerItem) []constructedInstance {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var removed []constructedInstance
+	constructed := c.constructed[:0]
+	for _, instance := range c.constructed {
+		if instance.item == item {
+			removed = append(removed, instance)
+			continue
+		}
+		constructed = append(constructed, instance)
+	}
+	c.constructed = constructed
+	return removed
+}
+
+// release moves the instances constructed from the items to the released instances,
+// which are no longer listed as constructed but are still disposed when the container is closed
+func (c *container) release(items map[*containerItem]bool) {
+	if len(items) == 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	constructed := c.constructed[:0]
+	for _, instance := range c.constructed {
+		if items[instance.item] {
+			c.released = append(c.released, instance)
+			continue
+		}
+		constructed = append(constructed, instance)
+	}
+	c.constructed = constructed
+}
+
+// dispose untracks the instances constructed from the item and finalizes and closes them
+func (c *container) dispose(item *containerItem) error {
+	var errs []error
+	for _, instance := range c.untrack(item) {
+		errs = append(errs, instance.dispose()...)
+	}
+	return c.joinErrors(errs)
+}
+
+// dispose runs the finalizer of the instance and closes it if it implements io.Closer
+func (instance constructedInstance) dispose() []error {
+	var errs []error
+	if instance.item.option.finalizer != nil {
+		err := instance.item.option.finalizer(instance.data)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	closer, ok := instance.data.(io.Closer)
+	if !ok {
+		return errs
+	}
+	err := closer.Close()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (c *container) ConstructedInstances() []any {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	instances := []any{}
+	for _, instance := range c.constructed {
+		instances = append(instances, instance.data)
+	}
+	return instances
+}
+
+func (c *container) Close() error {
+	c.mutex.Lock()
+	constructed := append(c.released, c.constructed...)
+	c.constructed = nil
+	c.released = nil
+	c.mutex.Unlock()
+
+	var errs []error
+	for i := range constructed {
+		instance := constructed[len(constructed)-1-i]
+		if c.disposeOrder == DisposeFIFO {
+			instance = constructed[i]
+		}
+		errs = append(errs, instance.dispose()...)
+	}
+	return c.joinErrors(errs)
+}
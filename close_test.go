@@ -0,0 +1,185 @@
+package di_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type Closer interface {
+	Close() error
+}
+
+type closer struct {
+	name   string
+	err    error
+	closed *[]string
+}
+
+func (c *closer) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+var CloserType = reflect.TypeOf((*Closer)(nil)).Elem()
+
+func TestConstructedInstances(t *testing.T) {
+	container := di.NewContainer(di.WithDefaultLifetime(di.LifetimeStatic))
+	for _, name := range []string{"one", "two", "three"} {
+		name := name
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample(name), nil
+		}, di.WithName(name))
+	}
+	require.Equal(t, 0, len(container.ConstructedInstances()))
+
+	for _, name := range []string{"three", "one"} {
+		_, err := container.ResolveByName(SampleInterfaceType, name)
+		require.NoError(t, err)
+	}
+
+	instances := container.ConstructedInstances()
+	require.Equal(t, 2, len(instances))
+	require.Equal(t, "three", instances[0].(SampleInterface).Name())
+	require.Equal(t, "one", instances[1].(SampleInterface).Name())
+}
+
+func TestClose(t *testing.T) {
+	t.Run("reverse construction order", func(t *testing.T) {
+		var closed []string
+		container := di.NewContainer(di.WithDefaultLifetime(di.LifetimeStatic))
+		for _, name := range []string{"one", "two", "three"} {
+			name := name
+			container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+				return &closer{name: name, closed: &closed}, nil
+			}, di.WithName(name))
+		}
+		for _, name := range []string{"two", "one", "three"} {
+			_, err := container.ResolveByName(CloserType, name)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, container.Close())
+		require.Equal(t, []string{"three", "one", "two"}, closed)
+	})
+	t.Run("dispose order", func(t *testing.T) {
+		type test struct {
+			name     string
+			options  []di.ContainerOption
+			expected []string
+		}
+		tests := []test{
+			{"default", nil, []string{"three", "two", "one"}},
+			{"lifo", []di.ContainerOption{di.WithDisposeOrder(di.DisposeLIFO)}, []string{"three", "two", "one"}},
+			{"fifo", []di.ContainerOption{di.WithDisposeOrder(di.DisposeFIFO)}, []string{"one", "two", "three"}},
+		}
+		for _, test := range tests {
+			test := test
+			t.Run(test.name, func(t *testing.T) {
+				var closed []string
+				container := di.NewContainer(test.options...)
+				for _, name := range []string{"one", "two", "three"} {
+					container.RegisterInstance(CloserType, &closer{name: name, closed: &closed}, di.WithName(name))
+				}
+				for _, name := range []string{"one", "two", "three"} {
+					_, err := container.ResolveByName(CloserType, name)
+					require.NoError(t, err)
+				}
+				require.NoError(t, container.Close())
+				require.Equal(t, test.expected, closed)
+			})
+		}
+	})
+	t.Run("skips unconstructed and per request", func(t *testing.T) {
+		var closed []string
+		container := di.NewContainer()
+		container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+			return &closer{name: "static", closed: &closed}, nil
+		}, di.WithName("static"), di.WithLifetime(di.LifetimeStatic))
+		container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+			return &closer{name: "transient", closed: &closed}, nil
+		}, di.WithName("transient"), di.WithLifetime(di.LifetimePerRequest))
+		container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+			return &closer{name: "unconstructed", closed: &closed}, nil
+		}, di.WithName("unconstructed"), di.WithLifetime(di.LifetimeStatic))
+
+		_, err := container.ResolveByName(CloserType, "static")
+		require.NoError(t, err)
+		_, err = container.ResolveByName(CloserType, "transient")
+		require.NoError(t, err)
+
+		require.NoError(t, container.Close())
+		require.Equal(t, []string{"static"}, closed)
+	})
+	t.Run("joins errors", func(t *testing.T) {
+		var closed []string
+		errOne := errors.New("one")
+		errTwo := errors.New("two")
+		var joined []error
+		container := di.NewContainer(di.WithErrorJoiner(func(errs []error) error {
+			joined = errs
+			return errs[0]
+		}))
+		container.RegisterInstance(CloserType, &closer{name: "one", err: errOne, closed: &closed}, di.WithName("one"))
+		container.RegisterInstance(CloserType, &closer{name: "two", err: errTwo, closed: &closed}, di.WithName("two"))
+		_, err := container.ResolveAll(CloserType)
+		require.NoError(t, err)
+
+		err = container.Close()
+		require.Error(t, err)
+		require.Equal(t, 2, len(joined))
+		require.ElementsMatch(t, []error{errOne, errTwo}, joined)
+	})
+	t.Run("default joiner", func(t *testing.T) {
+		var closed []string
+		errOne := errors.New("one")
+		errTwo := errors.New("two")
+		container := di.NewContainer()
+		container.RegisterInstance(CloserType, &closer{name: "one", err: errOne, closed: &closed})
+		container.RegisterInstance(CloserType, &closer{name: "two", err: errTwo, closed: &closed})
+		_, err := container.ResolveAll(CloserType)
+		require.NoError(t, err)
+
+		err = container.Close()
+		require.ErrorIs(t, err, errOne)
+		require.ErrorIs(t, err, errTwo)
+	})
+	t.Run("finalizer", func(t *testing.T) {
+		var finalized []string
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two"} {
+			container.RegisterInstance(SampleInterfaceType, NewSample(name),
+				di.WithName(name),
+				di.WithFinalizer(func(instance any) error {
+					finalized = append(finalized, instance.(SampleInterface).Name())
+					return nil
+				}))
+		}
+		_, err := container.ResolveByName(SampleInterfaceType, "one")
+		require.NoError(t, err)
+		_, err = container.ResolveByName(SampleInterfaceType, "two")
+		require.NoError(t, err)
+
+		require.NoError(t, container.Close())
+		require.Equal(t, []string{"two", "one"}, finalized)
+	})
+	t.Run("finalizer and closer", func(t *testing.T) {
+		var closed []string
+		finalizerErr := errors.New("finalizer")
+		container := di.NewContainer()
+		container.RegisterInstance(CloserType, &closer{name: "closer", closed: &closed},
+			di.WithFinalizer(func(instance any) error {
+				closed = append(closed, "finalizer")
+				return finalizerErr
+			}))
+		_, err := container.Resolve(CloserType)
+		require.NoError(t, err)
+
+		err = container.Close()
+		require.ErrorIs(t, err, finalizerErr)
+		require.Equal(t, []string{"finalizer", "closer"}, closed)
+	})
+}
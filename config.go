@@ -0,0 +1,56 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConfigAccessor reads primitive configuration values registered by name
+type ConfigAccessor struct {
+	resolver Resolver
+}
+
+// Config returns an accessor for the named configuration values registered with the resolver
+func Config(resolver Resolver) *ConfigAccessor {
+	return &ConfigAccessor{
+		resolver: resolver,
+	}
+}
+
+// GetString returns the string registered with the name
+func (a *ConfigAccessor) GetString(name string) (string, error) {
+	value, err := a.get(reflect.TypeOf(""), name)
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// GetInt returns the int registered with the name
+func (a *ConfigAccessor) GetInt(name string) (int, error) {
+	value, err := a.get(reflect.TypeOf(0), name)
+	if err != nil {
+		return 0, err
+	}
+	return value.(int), nil
+}
+
+// GetBool returns the bool registered with the name
+func (a *ConfigAccessor) GetBool(name string) (bool, error) {
+	value, err := a.get(reflect.TypeOf(false), name)
+	if err != nil {
+		return false, err
+	}
+	return value.(bool), nil
+}
+
+func (a *ConfigAccessor) get(t reflect.Type, name string) (any, error) {
+	value, err := a.resolver.ResolveByName(t, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s config '%s': %w", t, name, err)
+	}
+	if value == nil || reflect.TypeOf(value) != t {
+		return nil, fmt.Errorf("unable to get %s config '%s': registered value is '%T'", t, name, value)
+	}
+	return value, nil
+}
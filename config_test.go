@@ -0,0 +1,41 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	container := di.NewContainer()
+	container.RegisterInstance(StringType, "localhost", di.WithName("host"))
+	container.RegisterInstance(reflect.TypeOf(0), 8080, di.WithName("port"))
+	container.RegisterInstance(reflect.TypeOf(false), true, di.WithName("debug"))
+	config := di.Config(container)
+
+	t.Run("string", func(t *testing.T) {
+		host, err := config.GetString("host")
+		require.NoError(t, err)
+		require.Equal(t, "localhost", host)
+	})
+	t.Run("int", func(t *testing.T) {
+		port, err := config.GetInt("port")
+		require.NoError(t, err)
+		require.Equal(t, 8080, port)
+	})
+	t.Run("bool", func(t *testing.T) {
+		debug, err := config.GetBool("debug")
+		require.NoError(t, err)
+		require.True(t, debug)
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, err := config.GetString("port")
+		require.ErrorIs(t, err, di.ErrNameNotExist)
+		require.Contains(t, err.Error(), "unable to get string config 'port'")
+
+		_, err = di.Config(di.NewContainer()).GetBool("debug")
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
@@ -1,9 +1,15 @@
 package di
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Lifetime int
@@ -11,11 +17,20 @@ type Lifetime int
 const (
 	LifetimeStatic     Lifetime = 0
 	LifetimePerRequest Lifetime = 1
+
+	// LifetimeScoped items execute once per scope. See Container.Scope
+	// A static item can not depend on a scoped item because the static would outlive the scope.
+	LifetimeScoped Lifetime = 2
 )
 
 var (
 	ErrNotExist     = errors.New("item does not exist in the container")
 	ErrNameNotExist = errors.New("item with the given name does not exist in the container")
+	ErrKeyNotExist  = errors.New("item with the given key does not exist in the container")
+	ErrCircular     = errors.New("circular dependency detected")
+	ErrReadOnly     = errors.New("the container is read only")
+	ErrZeroValue    = errors.New("resolved the zero value")
+	ErrMaxDepth     = errors.New("maximum resolve depth exceeded")
 )
 
 // Container represents a dependency injection container
@@ -29,15 +44,174 @@ type Container interface {
 	// RegisterConstructor registers a type dynamically by instpecting the constructor signature
 	RegisterConstructor(constructor any, options ...InstanceRegistrationOption) error
 
+	// RegisterConstructorAs registers the constructor under each of the types. The return type must be assignable
+	// to every type and the registrations share the instances of the registration of the first type.
+	RegisterConstructorAs(constructor any, types []reflect.Type, options ...InstanceRegistrationOption) error
+
+	// AppendInstance registers an instance after any existing registrations of the type without replacing them.
+	// The returned handle can be passed to Remove to remove only this registration.
+	AppendInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) Handle
+
+	// Remove removes the single registration identified by the handle
+	Remove(handle Handle) error
+
+	// RegisterStruct registers a struct or struct pointer type that is resolved by allocating it and injecting its fields
+	RegisterStruct(t reflect.Type, options ...InstanceRegistrationOption) error
+
+	// RegisterSpec registers the instance, constructor or dynamic resolver described by the spec
+	RegisterSpec(spec RegistrationSpec) error
+
+	// RegisterNamedType registers the dynamic resolver for the type under a type name so it can be resolved
+	// with ResolveNamedType when the type is not known at compile time
+	RegisterNamedType(typeName string, t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption)
+
+	// ResolveNamedType resolves the type registered with the type name
+	ResolveNamedType(typeName string) (any, error)
+
+	// RegisterAlias registers the alias type so resolving it resolves the target type instead.
+	// The target type must be assignable to the alias type.
+	RegisterAlias(alias reflect.Type, target reflect.Type, options ...InstanceRegistrationOption) error
+
+	// RegisterError registers a type whose resolution always returns the given error
+	RegisterError(t reflect.Type, err error, options ...InstanceRegistrationOption)
+
+	// RegisterNamedInstances registers each instance in the map under its key as the name
+	RegisterNamedInstances(t reflect.Type, instances map[string]any, options ...InstanceRegistrationOption)
+
+	// OnResolve adds a hook that is called with the registered type and instance after every resolution of any
+	// registration, including dependencies. The instance returned by the hook replaces the resolved instance
+	// but not the cached instance. Hooks added to a container also apply to its scopes.
+	OnResolve(hook func(t reflect.Type, instance any) (any, error))
+
 	// ReplaceDynamic removes all instances and resplaces them with the given dynamic resolver
 	ReplaceDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption)
 
 	// ReplaceInstance removes all instances and replaces it with the given instance
 	ReplaceInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption)
 
+	// ReplaceByName replaces the registration with the given name with the instance, leaving other registrations intact
+	ReplaceByName(t reflect.Type, name string, instance any, options ...InstanceRegistrationOption)
+
+	// ReplaceDynamicByName replaces the registration with the given name with the dynamic resolver, leaving other registrations intact
+	ReplaceDynamicByName(t reflect.Type, name string, delegate FuncResolver, options ...InstanceRegistrationOption)
+
 	// RemoveAll
 	RemoveAll(t reflect.Type)
 
+	// Registrations returns the info of every registration for the type in resolution order
+	Registrations(t reflect.Type) []RegistrationInfo
+
+	// Types returns the types registered in the container and its parents sorted by name
+	Types() []reflect.Type
+
+	// ClearCache drops the cached instances of every registration so the next resolution constructs them again.
+	// It must not be called while instances are being resolved.
+	ClearCache()
+
+	// SetLifetime changes the lifetime of every registration of the type and clears their cached instances
+	SetLifetime(t reflect.Type, lifetime Lifetime)
+
+	// HasDefault returns true if Resolve would use a registration of the type, either an unnamed registration or
+	// a named registration when named fallback is enabled
+	HasDefault(t reflect.Type) bool
+
+	// FindDuplicates returns the types that have the same pointer like instance registered more than once, with a
+	// description of each of those registrations by name, key or registration index. Nothing is resolved.
+	FindDuplicates() map[reflect.Type][]string
+
+	// Count returns the number of named and unnamed registrations for the type without resolving them
+	Count(t reflect.Type) int
+
+	// ResolveWhere resolves the first registration of the given type whose info matches the predicate
+	ResolveWhere(t reflect.Type, predicate func(RegistrationInfo) bool) (any, error)
+
+	// ResolveAllByLifetime resolves the registrations of the type with the lifetime in the order of ResolveAll.
+	// It returns an empty slice if the type is registered but no registration has the lifetime.
+	ResolveAllByLifetime(t reflect.Type, lifetime Lifetime) ([]any, error)
+
+	// ResolveAllContext resolves all instances registered for the given type, stopping if the context is canceled.
+	// Constructors with a context.Context parameter receive the context.
+	ResolveAllContext(ctx context.Context, t reflect.Type) ([]any, error)
+
+	// ResolveAllChan resolves the registrations of the type one at a time as they are received from the channel.
+	// The channel is closed after the last instance or after a result with the first resolution error, so a stream that
+	// ends without an error is complete. Canceling the context stops resolution and closes the channel without a result
+	// for the remaining registrations. Constructors with a context.Context parameter receive the context.
+	ResolveAllChan(ctx context.Context, t reflect.Type) <-chan Result
+
+	// ResolveMapResults resolves all named instances of the given type as a map of results so a failing
+	// registration does not prevent the others from resolving
+	ResolveMapResults(t reflect.Type) (map[string]Result, error)
+
+	// ResolveMapOrdered resolves all named instances of the given type sorted by name
+	ResolveMapOrdered(t reflect.Type) ([]NamedValue, error)
+
+	// ResolveWith resolves the given type using the overrides for any dependency they can be assigned to
+	// before falling back to registrations. Overrides also supply the parameters of factories.
+	ResolveWith(t reflect.Type, overrides ...any) (any, error)
+
+	// ResolveGroup resolves every registration in the named group, including those of parent scopes, in registration order
+	ResolveGroup(group string) ([]any, error)
+
+	// ResolveBest resolves every registration of the type or of a type assignable to it and returns the instance
+	// with the highest score. The earliest registration wins a tie.
+	ResolveBest(t reflect.Type, score func(any) int) (any, error)
+
+	// ResolveMostSpecific resolves the registration of the type or of a type assignable to it whose registered type
+	// is the most specific. A concrete type is more specific than an interface and an interface with more methods is
+	// more specific than one with fewer. The earliest registration wins a tie.
+	ResolveMostSpecific(t reflect.Type) (any, error)
+
+	// ResolveAsync resolves the type like Resolve and returns a channel that receives the result of the background
+	// initialization of a constructor that returns a value and a <-chan error. The channel receives nil on success
+	// and is closed after the result. Other registrations report success immediately.
+	ResolveAsync(t reflect.Type) (any, <-chan error, error)
+
+	// ResolveTrace resolves the type like Resolve and returns a trace of the registration that was used and
+	// the registrations resolved for its dependencies. Unlike Explain it reflects the actual resolution.
+	ResolveTrace(t reflect.Type) (any, Trace, error)
+
+	// ResolveOrConstruct resolves the type or, if the type is not registered, returns the instance created by the fallback.
+	// The fallback is not registered and runs each time the type can not be resolved.
+	ResolveOrConstruct(t reflect.Type, fallback FuncResolver) (any, error)
+
+	// ResolveFirst resolves the first of the given types that is registered. Errors other than
+	// a missing registration are returned immediately. If no type is registered the errors are joined.
+	ResolveFirst(types ...reflect.Type) (any, error)
+
+	// ResolveCached resolves the instance registered for a given type and reports if it came from the static cache
+	ResolveCached(t reflect.Type) (any, bool, error)
+
+	// ResolveWithType resolves the instance registered for a given type along with the concrete type of the instance
+	ResolveWithType(t reflect.Type) (any, reflect.Type, error)
+
+	// ToJSON describes every registration and the parameter types of its constructor as JSON without resolving anything
+	ToJSON() ([]byte, error)
+
+	// Explain describes the item and dependencies Resolve would use for the given type without resolving anything
+	Explain(t reflect.Type) (Plan, error)
+
+	// Validate checks that the dependencies of every registered constructor can be resolved.
+	// A context.Context parameter that is not registered is assumed to be passed by ResolveAllContext.
+	Validate() error
+
+	// Stats returns the resolution metrics collected since the container was created WithStats
+	Stats() Stats
+
+	// ConstructedInstances returns the static instances that have been constructed and cached, in construction order
+	ConstructedInstances() []any
+
+	// Close runs finalizers and closes every constructed static instance that implements io.Closer in reverse construction order
+	// unless the container was created WithDisposeOrder
+	Close() error
+
+	// ReadOnly returns a view of the container that resolves but rejects registration and removal
+	ReadOnly() Container
+
+	// Scope returns a child container that resolves registrations from this container and its parents.
+	// Registrations in the scope override registrations of the same type in its parents.
+	Scope() Container
+
 	// Resolver is required as a Container must allow resolution
 	Resolver
 }
@@ -45,56 +219,477 @@ type Container interface {
 type FuncResolver func(Resolver) (any, error)
 
 type registrationOption struct {
-	name     string
-	key      string
-	resolver FuncResolver
-	lifetime Lifetime
+	typ                reflect.Type
+	name               string
+	key                string
+	resolver           FuncResolver
+	lifetime           Lifetime
+	registerAs         reflect.Type
+	constructor        reflect.Type
+	captureCollections bool
+	finalizer          func(any) error
+	metadata           map[string]any
+
+	// groups are the names of the groups the registration belongs to
+	groups []string
+
+	// itemKey is the typed key of a keyed registration
+	itemKey any
+
+	// instance is returned without running the resolver when isInstance is set
+	instance   any
+	isInstance bool
+
+	// cacheOnSuccessOnly caches a static instance only once it is constructed without error
+	cacheOnSuccessOnly bool
+
+	// shared registrations return the instances of another registration, which tracks and initializes them
+	shared bool
+
+	// cacheStrategy replaces the caching of the lifetime if set
+	cacheStrategy CacheStrategy
+
+	// optionalCollections resolves the collection parameters of a constructor as empty if their element type is not registered
+	optionalCollections bool
+
+	// alsoValueType registers the element type of a constructor's pointer return type
+	alsoValueType bool
+
+	// requireNonZero fails a static resolution that constructs the zero value
+	requireNonZero bool
+
+	// asValue registers a function passed to RegisterConstructor as an instance instead of calling it
+	asValue bool
+
+	// enabled decides at registration time if the registration is added
+	enabled func() bool
+
+	// index and registered record when the item was registered
+	index      uint64
+	registered time.Time
+}
+
+// NamedValue is a resolved instance and the name it was registered with
+type NamedValue struct {
+	Name  string
+	Value any
+}
+
+// Result is a resolved instance or the error returned while resolving it
+type Result struct {
+	Value any
+	Err   error
+}
+
+// Handle identifies a single registration in a container
+type Handle struct {
+	t    reflect.Type
+	item *containerItem
+}
+
+// RegistrationInfo describes a registration without resolving it
+type RegistrationInfo struct {
+	Type     reflect.Type
+	Name     string
+	Key      any
+	Lifetime Lifetime
+	Metadata map[string]any
+
+	// Index increases with each registration in the container and can be used to order registrations
+	Index uint64
+
+	// Time is the wall clock time of the registration
+	Time time.Time
+}
+
+// RegistrationSpec describes a registration as data. Exactly one of Instance, Constructor or Dynamic is used.
+// Type is required for instances and dynamic resolvers, for constructors it registers the constructor as the type.
+type RegistrationSpec struct {
+	Type        reflect.Type
+	Instance    any
+	Constructor any
+	Dynamic     FuncResolver
+	Name        string
+	Key         any
+
+	// Lifetime is the lifetime of the registration, nil uses the container's default lifetime
+	Lifetime *Lifetime
+	Metadata map[string]any
 }
 
 type containerItem struct {
 	data   any
 	err    error
+	once   sync.Once
 	option *registrationOption
+
+	// owner is the container the item was registered in, static items resolve their dependencies from it
+	owner *container
+
+	// succeeded is set once a static item registered WithCacheOnSuccessOnly has cached data
+	succeeded    atomic.Bool
+	successMutex sync.Mutex
+
+	// address caches the pointer returned when a static item is resolved by pointer
+	address     any
+	addressErr  error
+	addressOnce sync.Once
+
+	// cacheEntry is the instance cached by a registration WithCacheStrategy
+	cacheEntry *CacheEntry
+	cacheMutex sync.Mutex
+
+	// async is the status of the background initialization of the cached instance of an async constructor
+	async *asyncStatus
+}
+
+func (i *containerItem) resolve(c *container, parent *resolution) (any, error) {
+	data, _, err := i.resolveCached(c, parent)
+	return data, err
+}
+
+// resolveCached resolves the item and reports if the result came from the static cache
+func (i *containerItem) resolveCached(c *container, parent *resolution) (any, bool, error) {
+	parent, trace := parent.traceItem(c, i)
+	data, cached, err := i.resolveLifetime(c, parent)
+	if trace != nil {
+		trace.cached = cached
+	}
+	if err == nil {
+		data, err = c.onResolve(i.option.typ, data)
+	}
+	if stats := c.root().stats; stats != nil {
+		stats.record(i.option.typ, parent.depth()+1, cached)
+	}
+	return data, cached, err
 }
 
-func (i *containerItem) resolve(r Resolver) (any, error) {
+// resolveLifetime resolves the item according to its lifetime
+func (i *containerItem) resolveLifetime(c *container, parent *resolution) (any, bool, error) {
 
-	// was the error cached?
-	if i.err != nil {
-		return nil, i.err
+	// is the item already being resolved further up this chain?
+	if parent.resolving(i) {
+		return nil, false, fmt.Errorf("%w: '%s'", ErrCircular, i.option.key)
+	}
+	if depth := parent.depth() + 1; c.maxResolveDepth > 0 && depth > c.maxResolveDepth {
+		path := append(parent.path(), i.option.key)
+		return nil, false, fmt.Errorf("%w: depth %d of '%s' exceeds %d: %s",
+			ErrMaxDepth, depth, i.option.key, c.maxResolveDepth, strings.Join(path, " -> "))
 	}
 
-	// was the data cached?
-	if i.data != nil {
-		return i.data, nil
+	strategy, ok := i.strategy().(lifetimeCacheStrategy)
+	if !ok {
+		return i.resolveStrategy(i.owner, parent)
 	}
+	return strategy.resolve(i, c, parent)
+}
 
-	// execute the resolver
-	data, err := i.option.resolver(r)
+// resolve resolves the item with the cache of the lifetime
+func (s lifetimeCacheStrategy) resolve(i *containerItem, c *container, parent *resolution) (any, bool, error) {
+	switch s.lifetime {
+	case LifetimeStatic:
+		// statics are shared by every scope so they never resolve dependencies from the scope that asked first
+		c = i.owner
+		if i.option.cacheOnSuccessOnly {
+			return i.resolveOnSuccess(c, parent)
+		}
+	case LifetimeScoped:
+		if static := parent.static(); static != nil {
+			return nil, false, fmt.Errorf("static '%s' can not depend on scoped '%s' because it would outlive the scope",
+				static.option.key, i.option.key)
+		}
+		return c.resolveScoped(i, parent)
+	default:
+		if cache := parent.callCache(); cache != nil {
+			return i.resolveCall(c, parent, cache)
+		}
+		data, status, err := i.executeAsync(c, parent)
+		parent.reportAsync(status)
+		return data, false, err
+	}
+
+	// static lifetimes execute exactly once and cache the results
+	executed := false
+	i.once.Do(func() {
+		executed = true
+		i.data, i.async, i.err = i.construct(c, parent)
+		if i.err == nil {
+			c.root().track(i, i.data)
+		}
+	})
+	parent.reportAsync(i.async)
+	return i.data, !executed, i.err
+}
+
+// resolveOnSuccess resolves the static item and caches the result only if there is no error
+func (i *containerItem) resolveOnSuccess(c *container, parent *resolution) (any, bool, error) {
+	if i.succeeded.Load() {
+		parent.reportAsync(i.async)
+		return i.data, true, nil
+	}
+	i.successMutex.Lock()
+	defer i.successMutex.Unlock()
 
-	// if static lifetime, cache the results
-	if i.option.lifetime == LifetimeStatic {
-		i.data = data
-		i.err = err
+	// another resolution may have succeeded while waiting for the lock
+	if i.succeeded.Load() {
+		parent.reportAsync(i.async)
+		return i.data, true, nil
 	}
+	data, status, err := i.construct(c, parent)
+	if err != nil {
+		return nil, false, err
+	}
+	i.data = data
+	i.async = status
+	i.succeeded.Store(true)
+	parent.reportAsync(status)
+	c.root().track(i, data)
+	return data, false, nil
+}
+
+// resolveCall resolves the per request item once for the call that owns the cache
+func (i *containerItem) resolveCall(c *container, parent *resolution, cache *callCache) (any, bool, error) {
+	instance := cache.instance(i)
+	executed := false
+	instance.once.Do(func() {
+		executed = true
+		instance.data, instance.async, instance.err = i.executeAsync(c, parent)
+	})
+	parent.reportAsync(instance.async)
+	return instance.data, !executed, instance.err
+}
+
+// reset clears the cached instance so the next static resolution executes the resolver again
+func (i *containerItem) reset() {
+	i.data = nil
+	i.err = nil
+	i.async = nil
+	i.once = sync.Once{}
+	i.succeeded.Store(false)
+	i.address = nil
+	i.addressErr = nil
+	i.addressOnce = sync.Once{}
+	i.cacheMutex.Lock()
+	i.cacheEntry = nil
+	i.cacheMutex.Unlock()
+}
+
+// resolveAddress resolves the item and returns a pointer to a copy of the instance.
+// Static items always return the same pointer so changes made through it are shared by every pointer resolution,
+// but they are not seen by resolutions of the value itself. Other lifetimes return a pointer to a new copy.
+func (i *containerItem) resolveAddress(c *container, parent *resolution) (any, error) {
+	data, err := i.resolve(c, parent)
+	if err != nil {
+		return nil, err
+	}
+	if i.option.lifetime != LifetimeStatic {
+		return addressOf(i.option.typ, data)
+	}
+	i.addressOnce.Do(func() {
+		i.address, i.addressErr = addressOf(i.option.typ, data)
+	})
+	return i.address, i.addressErr
+}
+
+// addressOf copies the instance into a new value of type t and returns a pointer to it
+func addressOf(t reflect.Type, instance any) (any, error) {
+	pointer := reflect.New(t)
+	if instance != nil {
+		v := reflect.ValueOf(instance)
+		if !v.Type().AssignableTo(t) {
+			return nil, fmt.Errorf("unable to assign instance of type '%s' to '%s'", v.Type(), t)
+		}
+		pointer.Elem().Set(v)
+	}
+	return pointer.Interface(), nil
+}
+
+// info returns the registration info of the item
+func (i *containerItem) info() RegistrationInfo {
+	metadata := map[string]any{}
+	for k, v := range i.option.metadata {
+		metadata[k] = v
+	}
+	return RegistrationInfo{
+		Type:     i.option.typ,
+		Name:     i.option.name,
+		Key:      i.option.itemKey,
+		Lifetime: i.option.lifetime,
+		Metadata: metadata,
+		Index:    i.option.index,
+		Time:     i.option.registered,
+	}
+}
 
+// execute runs the resolver, marking the item in progress for any nested resolution
+func (i *containerItem) execute(c *container, parent *resolution) (any, error) {
+	data, _, err := i.executeAsync(c, parent)
 	return data, err
 }
 
+// executeAsync runs the resolver like execute and returns the status of the background initialization
+// reported by an async constructor, nil for other resolvers
+func (i *containerItem) executeAsync(c *container, parent *resolution) (any, *asyncStatus, error) {
+	// instances have no dependencies so there is nothing to resolve
+	if i.option.isInstance {
+		return i.option.instance, nil, nil
+	}
+	r := &resolution{
+		container: c,
+		parent:    parent,
+		item:      i,
+	}
+	data, err := i.option.resolver(r)
+	return data, r.async, err
+}
+
 // containerItemGroup holds a group of container items
 type containerItemGroup struct {
+	t          reflect.Type
 	items      []*containerItem
 	namedItems map[string]*containerItem
+	keyedItems map[any]*containerItem
+
+	// keys holds the keys of keyedItems in registration order
+	keys []any
+}
+
+// ordered returns the unnamed items in registration order followed by the named items sorted by name
+// and the keyed items in registration order
+func (g *containerItemGroup) ordered() []*containerItem {
+	var items []*containerItem
+	items = append(items, g.items...)
+	for _, name := range g.names() {
+		items = append(items, g.namedItems[name])
+	}
+	for _, key := range g.keys {
+		items = append(items, g.keyedItems[key])
+	}
+	return items
+}
+
+// sliceOrder returns a snapshot of the items of the group in the order they are resolved into a slice,
+// so registrations made while the items resolve do not change the slice being resolved
+func (c *container) sliceOrder(g *containerItemGroup) []*containerItem {
+	if !c.sliceOrderByName {
+		return g.ordered()
+	}
+	var items []*containerItem
+	for _, name := range g.names() {
+		items = append(items, g.namedItems[name])
+	}
+	for _, key := range g.keys {
+		items = append(items, g.keyedItems[key])
+	}
+	items = append(items, g.items...)
+	return items
+}
+
+// defaultItem returns the item used to resolve a single instance of the group.
+// The first unnamed item is preferred, otherwise the named item with the lowest name is used.
+func (g *containerItemGroup) defaultItem(t reflect.Type) (*containerItem, error) {
+	if len(g.items) > 0 {
+		return g.items[0], nil
+	}
+	names := g.names()
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotExist, t.String())
+	}
+	return g.namedItems[names[0]], nil
+}
+
+// defaultItem returns the default item of the group. Named items are not used if the container was created WithoutNamedFallback.
+func (c *container) defaultItem(g *containerItemGroup, t reflect.Type) (*containerItem, error) {
+	if c.withoutNamedFallback && len(g.items) == 0 {
+		return nil, fmt.Errorf("%w: '%s' has no unnamed registration", ErrNotExist, t.String())
+	}
+	return g.defaultItem(t)
 }
 
 type container struct {
-	groups         map[string]*containerItemGroup
+	groups         map[reflect.Type]*containerItemGroup
 	defaultOptions []DefaultRegistrationOption
+
+	// typeDefaults are applied to registrations of the type after the default options
+	typeDefaults map[reflect.Type][]InstanceRegistrationOption
+	errorJoiner  func([]error) error
+	index        uint64
+	autowire     bool
+	dedup        bool
+	assignable   bool
+	namePrefix   string
+
+	// withoutNamedFallback stops Resolve from using a named item when there is no unnamed item
+	withoutNamedFallback bool
+
+	// sliceOrderByName orders ResolveAll and slice parameters by name
+	sliceOrderByName bool
+
+	// mapKeyMetadata is the metadata key whose value is used as the map key by ResolveMap
+	mapKeyMetadata string
+
+	panicOnResolveError bool
+	disposeOrder        DisposeOrder
+
+	// namedTypes holds the types registered with RegisterNamedType by type name
+	namedTypes map[string]reflect.Type
+
+	// postConstruct initializes static instances after they are constructed
+	postConstruct bool
+
+	// sliceOptions selects the registrations resolved into slices, nil resolves every registration
+	sliceOptions *SliceOptions
+
+	// maxResolveDepth is the longest dependency chain that can be resolved, zero is unlimited
+	maxResolveDepth int
+
+	// resolveHooks are called after every resolution, see OnResolve
+	resolveHooks []func(t reflect.Type, instance any) (any, error)
+
+	// stats collects resolution metrics if the container was created WithStats
+	stats *statsCollector
+
+	// parent is the container the scope was created from, nil for the root container
+	parent *container
+
+	// scoped holds the instances of scoped items resolved in this scope
+	scoped map[*containerItem]*scopedInstance
+
+	// constructed holds the instances in the order they were constructed
+	constructed []constructedInstance
+
+	// released holds the instances dropped from the cache, they are still disposed when the container is closed
+	released []constructedInstance
+	mutex    sync.Mutex
 }
 
 type InstanceRegistrationOption func(*registrationOption)
 type DefaultRegistrationOption func(*registrationOption)
 
+// ContainerOption configures a container when it is created
+type ContainerOption interface {
+	apply(*container)
+}
+
+type containerOption func(*container)
+
+func (o containerOption) apply(c *container) {
+	o(c)
+}
+
+// apply adds the default registration option to the container so it is applied to every registration
+func (o DefaultRegistrationOption) apply(c *container) {
+	c.defaultOptions = append(c.defaultOptions, o)
+}
+
+// WithErrorJoiner sets the function used to combine errors from operations that aggregate errors like Validate and Close.
+// The default is errors.Join
+func WithErrorJoiner(joiner func([]error) error) ContainerOption {
+	return containerOption(func(c *container) {
+		c.errorJoiner = joiner
+	})
+}
+
 // WithLifetime sets the lifetime of the registration
 func WithLifetime(lifetime Lifetime) InstanceRegistrationOption {
 	return withLifetime(lifetime)
@@ -105,6 +700,13 @@ func WithDefaultLifetime(lifetime Lifetime) DefaultRegistrationOption {
 	return withLifetime(lifetime)
 }
 
+// WithDefaultLifetimeFunc sets the lifetime of each registration to the lifetime returned for the registered type
+func WithDefaultLifetimeFunc(lifetime func(reflect.Type) Lifetime) DefaultRegistrationOption {
+	return func(i *registrationOption) {
+		i.lifetime = lifetime(i.typ)
+	}
+}
+
 func withLifetime(lifetime Lifetime) func(i *registrationOption) {
 	return func(i *registrationOption) {
 		i.lifetime = lifetime
@@ -117,129 +719,1145 @@ func WithName(name string) InstanceRegistrationOption {
 	}
 }
 
-// NewContainer returns a new container with the specified default options applied to all objects registered in the container
-func NewContainer(options ...DefaultRegistrationOption) Container {
-
-	return &container{
-		groups:         map[string]*containerItemGroup{},
-		defaultOptions: options,
+// WithRegisterAs registers a constructor under the given type instead of its return type.
+// The return type of the constructor must be assignable to the given type.
+func WithRegisterAs(t reflect.Type) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.registerAs = t
 	}
 }
 
-func (c *container) RegisterConstructor(constructor any, options ...InstanceRegistrationOption) error {
-	t := reflect.TypeOf(constructor)
-	err := validateDelegateTypeIsConstructor(c, t)
-	if err != nil {
-		return err
+// WithCaptureCollections resolves the slice and map parameters of a constructor once and reuses them for every
+// construction of the registration, even if more items are registered later
+func WithCaptureCollections() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.captureCollections = true
 	}
+}
 
-	delegate := func(r Resolver) (any, error) {
-		return Invoke(r, constructor)
+// WithFinalizer sets a function that is called with the constructed static instance when the container is closed.
+// The finalizer runs before the instance is closed if it also implements io.Closer.
+func WithFinalizer(finalizer func(any) error) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.finalizer = finalizer
 	}
-
-	returnType := t.Out(0)
-	c.RegisterDynamic(returnType, delegate, options...)
-	return nil
 }
 
-func validateDelegateTypeIsConstructor(r Resolver, t reflect.Type) error {
-	err := validateDelegateType(r, t)
-	if err != nil {
-		return err
+// WithKey registers the item under the given comparable key. A keyed registration is resolved with ResolveByKey
+// and is included in ResolveAll.
+func WithKey(key any) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.itemKey = key
 	}
-	outCount := t.NumOut()
-	if outCount == 0 {
-		return fmt.Errorf("function must have a return value and optional error")
-	} else if outCount == 2 {
-		errorType := t.Out(1)
-		if !errorType.Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-			return fmt.Errorf("if a function has two return parameters, the second must implement error")
-		}
-	} else if outCount != 1 {
-		return fmt.Errorf("function must have a return value and optional error")
+}
+
+// WithGroup adds the registration to the named group. Groups can contain registrations of different types
+// and are resolved with ResolveGroup.
+func WithGroup(group string) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.groups = append(i.groups, group)
 	}
-	return nil
 }
 
-func (c *container) RegisterDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
-	// try to find the existing container item group
-	key := t.String()
+// WithCacheOnSuccessOnly caches a static registration only after it resolves without error.
+// A failed resolution is not cached so the next resolution tries again.
+func WithCacheOnSuccessOnly() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.cacheOnSuccessOnly = true
+	}
+}
 
-	o := &registrationOption{
-		key:      key,
-		resolver: delegate,
+// WithOptionalCollections resolves the slice and map parameters of a constructor to empty collections when their
+// element type is not registered instead of failing. Other constructors are not affected.
+func WithOptionalCollections() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.optionalCollections = true
 	}
+}
 
-	// apply the default options
-	for _, option := range c.defaultOptions {
-		option(o)
+// WithAlsoValueType registers a constructor that returns a pointer under the pointer type and under its element type.
+// Resolving the element type dereferences the pointer, so consumers can request the value.
+func WithAlsoValueType() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.alsoValueType = true
 	}
+}
 
-	// apply the override options
-	for _, option := range options {
-		option(o)
+// WithRequireNonZero fails a static resolution that constructs the zero value of its type, for example an unset config struct.
+// The error wraps ErrZeroValue.
+func WithRequireNonZero() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.requireNonZero = true
 	}
+}
 
-	group, ok := c.groups[key]
-	if !ok {
-		group = &containerItemGroup{
-			items:      []*containerItem{},
-			namedItems: map[string]*containerItem{},
-		}
-		c.groups[key] = group
+// WithAsValue registers a function passed to RegisterConstructor as an instance of its function type instead of
+// calling it to construct instances. RegisterInstance always registers functions as values.
+func WithAsValue() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.asValue = true
 	}
+}
 
-	item := &containerItem{
-		option: o,
+// WithEnabled evaluates the predicate when the item is registered and skips the registration if it returns false.
+// The predicate is not evaluated again during resolution.
+func WithEnabled(enabled func() bool) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.enabled = enabled
 	}
+}
 
-	// if the name is empty, append to the list of unnamed items
-	if o.name == "" {
-		group.items = append(group.items, item)
-	} else {
-		group.namedItems[o.name] = item
+// WithMetadata adds the key and value to the metadata of the registration
+func WithMetadata(key string, value any) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		if i.metadata == nil {
+			i.metadata = map[string]any{}
+		}
+		i.metadata[key] = value
 	}
 }
 
-func (c *container) RegisterInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
-	c.RegisterDynamic(t, func(r Resolver) (any, error) {
-		return instance, nil
-	}, options...)
+// WithTypeDefaults applies the options to every registration of the type. They are applied after the default options
+// and before the options of the registration.
+func WithTypeDefaults(t reflect.Type, options ...InstanceRegistrationOption) ContainerOption {
+	return containerOption(func(c *container) {
+		if c.typeDefaults == nil {
+			c.typeDefaults = map[reflect.Type][]InstanceRegistrationOption{}
+		}
+		c.typeDefaults[t] = append(c.typeDefaults[t], options...)
+	})
 }
 
-func (c *container) ReplaceDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
-	c.RemoveAll(t)
-	c.RegisterDynamic(t, delegate, options...)
+// WithPostConstruct calls Init() error and then Start(context.Context) error on each static instance that has them
+// once, right after it is constructed and before it is returned. Dependencies are constructed first so they are
+// initialized first. An error is returned from the resolution that constructed the instance.
+func WithPostConstruct() ContainerOption {
+	return containerOption(func(c *container) {
+		c.postConstruct = true
+	})
 }
 
-func (c *container) ReplaceInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
-	c.RemoveAll(t)
-	c.RegisterInstance(t, instance, options...)
+// WithAutowire constructs unregistered struct and struct pointer types by injecting their fields
+// instead of failing resolution
+func WithAutowire() ContainerOption {
+	return containerOption(func(c *container) {
+		c.autowire = true
+	})
 }
 
-func (c *container) RemoveAll(t reflect.Type) {
-	key := t.String()
-	delete(c.groups, key)
+// WithAssignableResolution resolves a type that is not registered from a registration of another type that is assignable to it.
+// For example an interface can be resolved from a registered struct pointer that implements it.
+// A registration of the exact type always wins. If several registered types are assignable, the one registered first wins.
+func WithAssignableResolution() ContainerOption {
+	return containerOption(func(c *container) {
+		c.assignable = true
+	})
 }
 
-func (c *container) group(t reflect.Type) (*containerItemGroup, error) {
-	key := t.String()
-	group, ok := c.groups[key]
-	if !ok {
-		return nil, fmt.Errorf("%w: '%s'", ErrNotExist, key)
-	}
-	return group, nil
+// WithResolveAllDedup removes repeated instances from ResolveAll by pointer identity
+func WithResolveAllDedup() ContainerOption {
+	return containerOption(func(c *container) {
+		c.dedup = true
+	})
 }
 
-func (c *container) Resolve(t reflect.Type) (any, error) {
-	results, err := c.ResolveAll(t)
-	if err != nil {
-		return nil, err
-	}
-	return results[0], nil
+// WithPanicOnResolveError panics with the resolution error instead of returning it from the container's resolve methods
+// and any Invoke that uses the container. It is intended for wiring in main where failures should abort.
+func WithPanicOnResolveError() ContainerOption {
+	return containerOption(func(c *container) {
+		c.panicOnResolveError = true
+	})
 }
 
-func (c *container) ResolveByName(t reflect.Type, name string) (any, error) {
+// WithMaxResolveDepth fails a resolution whose dependency chain is longer than n registrations
+// with an error wrapping ErrMaxDepth that lists the chain.
+func WithMaxResolveDepth(n int) ContainerOption {
+	return containerOption(func(c *container) {
+		c.maxResolveDepth = n
+	})
+}
+
+// WithNamePrefix prepends the prefix to the name of every named registration.
+// Named registrations are resolved by the prefixed name.
+func WithNamePrefix(prefix string) ContainerOption {
+	return containerOption(func(c *container) {
+		c.namePrefix = prefix
+	})
+}
+
+// WithoutNamedFallback resolves only unnamed registrations with Resolve. By default a type with only named registrations
+// resolves to the named registration with the lowest name.
+func WithoutNamedFallback() ContainerOption {
+	return containerOption(func(c *container) {
+		c.withoutNamedFallback = true
+	})
+}
+
+// WithSliceOrderByName orders the instances returned by ResolveAll and injected into slice and variadic parameters
+// by registration name. Named registrations come first sorted by name, followed by keyed and then unnamed registrations
+// in registration order.
+func WithSliceOrderByName() ContainerOption {
+	return containerOption(func(c *container) {
+		c.sliceOrderByName = true
+	})
+}
+
+// WithMapKeyFromMetadata uses the value of the metadata key as the map key when resolving a map instead of the registration name.
+// Registrations without the metadata are keyed by name, unnamed registrations without the metadata are left out.
+func WithMapKeyFromMetadata(key string) ContainerOption {
+	return containerOption(func(c *container) {
+		c.mapKeyMetadata = key
+	})
+}
+
+// NewContainer returns a new container with the specified default options applied to all objects registered in the container
+func NewContainer(options ...ContainerOption) Container {
+	c := &container{
+		groups:      map[reflect.Type]*containerItemGroup{},
+		errorJoiner: defaultErrorJoiner,
+	}
+	for _, option := range options {
+		option.apply(c)
+	}
+	return c
+}
+
+// defaultErrorJoiner joins errors with errors.Join
+func defaultErrorJoiner(errs []error) error {
+	return errors.Join(errs...)
+}
+
+// FromMap returns a new container with each instance in the map registered as an unnamed instance of its type
+func FromMap(m map[reflect.Type]any, options ...ContainerOption) Container {
+	c := NewContainer(options...)
+	for t, instance := range m {
+		c.RegisterInstance(t, instance)
+	}
+	return c
+}
+
+// joinErrors combines the errors with the container's error joiner, returning nil if there are no errors
+func (c *container) joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return c.errorJoiner(errs)
+}
+
+// joinResolverErrors combines the errors with the error joiner of the container behind the resolver,
+// or with errors.Join if the resolver is not backed by a container created with NewContainer
+func joinResolverErrors(resolver Resolver, errs []error) error {
+	switch r := resolver.(type) {
+	case *container:
+		return r.joinErrors(errs)
+	case *readOnlyContainer:
+		return r.container.joinErrors(errs)
+	case *resolution:
+		return r.container.joinErrors(errs)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *container) RegisterConstructor(constructor any, options ...InstanceRegistrationOption) error {
+	_, err := c.registerConstructor(constructor, options...)
+	return err
+}
+
+// registerConstructor registers the constructor and returns the item registered for its return type
+func (c *container) registerConstructor(constructor any, options ...InstanceRegistrationOption) (*containerItem, error) {
+	t := reflect.TypeOf(constructor)
+
+	// check the options for overrides that change how the constructor is registered
+	o := &registrationOption{}
+	for _, option := range options {
+		option(o)
+	}
+
+	// a function registered as a value can have any signature
+	if o.asValue {
+		err := validateDelegateType(c, t)
+		if err != nil {
+			return nil, err
+		}
+		valueType := t
+		if o.registerAs != nil {
+			if !t.AssignableTo(o.registerAs) {
+				return nil, fmt.Errorf("function type '%s' is not assignable to '%s'", t, o.registerAs)
+			}
+			valueType = o.registerAs
+		}
+		return c.registerInstance(valueType, constructor, options...), nil
+	}
+
+	err := validateDelegateTypeIsConstructor(c, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoke []InvokeOption
+	if o.optionalCollections {
+		invoke = append(invoke, withOptionalCollections())
+	}
+	delegate := func(r Resolver) (any, error) {
+		return Invoke(r, constructor, invoke...)
+	}
+	if isAsyncConstructor(t) {
+		delegate = asyncDelegate(constructor, newInvokeOptions(invoke...))
+	} else if o.captureCollections {
+		capture := &collectionCapture{
+			values: map[int][]reflect.Value{},
+		}
+		invokeOptions := newInvokeOptions(invoke...)
+		delegate = func(r Resolver) (any, error) {
+			return capture.invoke(r, constructor, invokeOptions)
+		}
+	}
+
+	returnType := t.Out(0)
+	if o.registerAs != nil {
+		if !returnType.AssignableTo(o.registerAs) {
+			return nil, fmt.Errorf("constructor return type '%s' is not assignable to '%s'", returnType, o.registerAs)
+		}
+		returnType = o.registerAs
+	}
+
+	if o.alsoValueType && returnType.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("constructor return type '%s' must be a pointer to also register its value type", returnType)
+	}
+
+	// record the constructor signature so dependencies can be inspected without resolving
+	options = append([]InstanceRegistrationOption{withConstructor(t)}, options...)
+	item := c.register(returnType, delegate, options...)
+	if o.alsoValueType {
+		c.registerValueType(item, returnType, options...)
+	}
+	return item, nil
+}
+
+func (c *container) RegisterConstructorAs(constructor any, types []reflect.Type, options ...InstanceRegistrationOption) error {
+	if len(types) == 0 {
+		return fmt.Errorf("at least one type is required to register the constructor")
+	}
+	t := reflect.TypeOf(constructor)
+	err := validateDelegateTypeIsConstructor(c, t)
+	if err != nil {
+		return err
+	}
+	for _, target := range types {
+		if !t.Out(0).AssignableTo(target) {
+			return fmt.Errorf("constructor return type '%s' is not assignable to '%s'", t.Out(0), target)
+		}
+	}
+	source, err := c.registerConstructor(constructor, append(options, WithRegisterAs(types[0]))...)
+	if err != nil {
+		return err
+	}
+
+	// the other types resolve the first registration so they share its instances
+	options = append([]InstanceRegistrationOption{withConstructor(t)}, options...)
+	options = append(options, withShared())
+	for _, target := range types[1:] {
+		c.register(target, func(r Resolver) (any, error) {
+			parent, _ := r.(*resolution)
+			return source.resolve(c, parent)
+		}, options...)
+	}
+	return nil
+}
+
+// withShared marks a registration that returns the instances of another registration
+func withShared() InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.shared = true
+	}
+}
+
+// registerValueType registers the element type of the pointer type t with a resolver that dereferences the instance of the item.
+// The value is copied on every resolution so the item's lifetime decides how often the pointer is constructed.
+func (c *container) registerValueType(item *containerItem, t reflect.Type, options ...InstanceRegistrationOption) {
+	options = append(options, WithLifetime(LifetimePerRequest))
+	c.register(t.Elem(), func(r Resolver) (any, error) {
+		parent, _ := r.(*resolution)
+		data, err := item.resolve(c, parent)
+		if err != nil {
+			return nil, err
+		}
+		value := reflect.ValueOf(data)
+		if !value.IsValid() || value.IsNil() {
+			return nil, fmt.Errorf("unable to dereference nil '%s'", t)
+		}
+		return value.Elem().Interface(), nil
+	}, options...)
+}
+
+func withConstructor(t reflect.Type) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.constructor = t
+	}
+}
+
+// ValidateConstructor returns an error if fn can not be registered with RegisterConstructor.
+// A constructor is a function that returns a value and optionally an error.
+func ValidateConstructor(fn any) error {
+	return validateDelegateTypeIsConstructor(nil, reflect.TypeOf(fn))
+}
+
+func validateDelegateTypeIsConstructor(r Resolver, t reflect.Type) error {
+	err := validateDelegateType(r, t)
+	if err != nil {
+		return err
+	}
+	outCount := t.NumOut()
+	if outCount == 0 {
+		return fmt.Errorf("function must have a return value and optional error")
+	} else if outCount == 2 {
+		errorType := t.Out(1)
+		if !errorType.Implements(reflect.TypeOf((*error)(nil)).Elem()) && errorType != errorChanType {
+			return fmt.Errorf("if a function has two return parameters, the second must implement error or be a <-chan error")
+		}
+	} else if outCount != 1 {
+		return fmt.Errorf("function must have a return value and optional error")
+	}
+	return nil
+}
+
+func (c *container) RegisterDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	c.register(t, delegate, options...)
+}
+
+// register adds a dynamic resolver for the type and returns the new item
+func (c *container) register(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) *containerItem {
+	// the key is only used to describe the type, groups are keyed by the type itself
+	key := t.String()
+
+	// the index is shared by every scope so registrations can be ordered across scopes
+	root := c.root()
+	root.index++
+	o := &registrationOption{
+		typ:        t,
+		key:        key,
+		resolver:   delegate,
+		index:      root.index,
+		registered: time.Now(),
+	}
+
+	// apply the default options
+	for _, option := range c.defaultOptions {
+		option(o)
+	}
+
+	// apply the default options of the type
+	for _, option := range c.typeDefaults[t] {
+		option(o)
+	}
+
+	// apply the override options
+	for _, option := range options {
+		option(o)
+	}
+
+	if o.name != "" {
+		o.name = c.namePrefix + o.name
+	}
+
+	item := &containerItem{
+		option: o,
+		owner:  c,
+	}
+
+	// disabled registrations are never added to the container
+	if o.enabled != nil && !o.enabled() {
+		return item
+	}
+
+	group, ok := c.groups[t]
+	if !ok {
+		group = &containerItemGroup{
+			t:          t,
+			items:      []*containerItem{},
+			namedItems: map[string]*containerItem{},
+			keyedItems: map[any]*containerItem{},
+		}
+		c.groups[t] = group
+	}
+
+	// keyed items take precedence, if the name is empty, append to the list of unnamed items
+	if o.itemKey != nil {
+		if _, ok := group.keyedItems[o.itemKey]; !ok {
+			group.keys = append(group.keys, o.itemKey)
+		}
+		group.keyedItems[o.itemKey] = item
+	} else if o.name == "" {
+		group.items = append(group.items, item)
+	} else {
+		group.namedItems[o.name] = item
+	}
+	return item
+}
+
+func (c *container) RegisterInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
+	c.registerInstance(t, instance, options...)
+}
+
+func (c *container) OnResolve(hook func(t reflect.Type, instance any) (any, error)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.resolveHooks = append(c.resolveHooks, hook)
+}
+
+// onResolve calls the resolve hooks of the root container down to this container in the order they were added
+func (c *container) onResolve(t reflect.Type, instance any) (any, error) {
+	if c.parent != nil {
+		var err error
+		instance, err = c.parent.onResolve(t, instance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.mutex.Lock()
+	hooks := c.resolveHooks
+	c.mutex.Unlock()
+	for _, hook := range hooks {
+		var err error
+		instance, err = hook(t, instance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+func (c *container) RegisterNamedInstances(t reflect.Type, instances map[string]any, options ...InstanceRegistrationOption) {
+	names := make([]string, 0, len(instances))
+	for name := range instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.registerInstance(t, instances[name], append(options, WithName(name))...)
+	}
+}
+
+// registerInstance registers the instance so it is returned without invoking a resolver
+func (c *container) registerInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) *containerItem {
+	options = append([]InstanceRegistrationOption{withInstance(instance)}, options...)
+	return c.register(t, func(r Resolver) (any, error) {
+		return instance, nil
+	}, options...)
+}
+
+func withInstance(instance any) InstanceRegistrationOption {
+	return func(i *registrationOption) {
+		i.instance = instance
+		i.isInstance = true
+	}
+}
+
+func (c *container) AppendInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) Handle {
+	item := c.registerInstance(t, instance, options...)
+	return Handle{
+		t:    t,
+		item: item,
+	}
+}
+
+func (c *container) Remove(handle Handle) error {
+	if handle.item == nil {
+		return fmt.Errorf("%w: invalid handle", ErrNotExist)
+	}
+	group, err := c.localGroup(handle.t)
+	if err != nil {
+		return err
+	}
+	key := handle.item.option.itemKey
+	if key != nil {
+		if group.keyedItems[key] != handle.item {
+			return fmt.Errorf("%w: '%v'", ErrKeyNotExist, key)
+		}
+		delete(group.keyedItems, key)
+		for i, k := range group.keys {
+			if k == key {
+				group.keys = append(group.keys[:i:i], group.keys[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+	name := handle.item.option.name
+	if name != "" {
+		if group.namedItems[name] != handle.item {
+			return fmt.Errorf("%w: '%s'", ErrNameNotExist, name)
+		}
+		delete(group.namedItems, name)
+		return nil
+	}
+	for i, item := range group.items {
+		if item != handle.item {
+			continue
+		}
+		group.items = append(group.items[:i:i], group.items[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("%w: '%s'", ErrNotExist, handle.t.String())
+}
+
+func (c *container) RegisterStruct(t reflect.Type, options ...InstanceRegistrationOption) error {
+	if !canAutowire(t) {
+		return fmt.Errorf("type '%s' must be a struct or a pointer to a struct", t)
+	}
+	c.RegisterDynamic(t, func(r Resolver) (any, error) {
+		return newInjected(r, t)
+	}, options...)
+	return nil
+}
+
+func (c *container) RegisterSpec(spec RegistrationSpec) error {
+	forms := 0
+	for _, set := range []bool{spec.Instance != nil, spec.Constructor != nil, spec.Dynamic != nil} {
+		if set {
+			forms++
+		}
+	}
+	if forms != 1 {
+		return fmt.Errorf("registration spec must set exactly one of Instance, Constructor or Dynamic")
+	}
+
+	var options []InstanceRegistrationOption
+	if spec.Lifetime != nil {
+		options = append(options, WithLifetime(*spec.Lifetime))
+	}
+	if spec.Name != "" {
+		options = append(options, WithName(spec.Name))
+	}
+	if spec.Key != nil {
+		options = append(options, WithKey(spec.Key))
+	}
+	for key, value := range spec.Metadata {
+		options = append(options, WithMetadata(key, value))
+	}
+
+	if spec.Constructor != nil {
+		if spec.Type != nil {
+			options = append(options, WithRegisterAs(spec.Type))
+		}
+		return c.RegisterConstructor(spec.Constructor, options...)
+	}
+	if spec.Type == nil {
+		return fmt.Errorf("registration spec must set Type for an instance or dynamic registration")
+	}
+	if spec.Dynamic != nil {
+		c.RegisterDynamic(spec.Type, spec.Dynamic, options...)
+		return nil
+	}
+	c.RegisterInstance(spec.Type, spec.Instance, options...)
+	return nil
+}
+
+func (c *container) RegisterNamedType(typeName string, t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	if c.namedTypes == nil {
+		c.namedTypes = map[string]reflect.Type{}
+	}
+	c.namedTypes[typeName] = t
+
+	// the type name is also the registration name so the delegate does not replace the default registration of the type
+	options = append(options, WithName(typeName))
+	c.RegisterDynamic(t, delegate, options...)
+}
+
+func (c *container) ResolveNamedType(typeName string) (any, error) {
+	for current := c; current != nil; current = current.parent {
+		t, ok := current.namedTypes[typeName]
+		if !ok {
+			continue
+		}
+		instance, err := c.resolveByName(nil, t, c.namePrefix+typeName)
+		return instance, c.check(err)
+	}
+	return nil, c.check(fmt.Errorf("%w: type name '%s'", ErrNotExist, typeName))
+}
+
+func (c *container) RegisterAlias(alias reflect.Type, target reflect.Type, options ...InstanceRegistrationOption) error {
+	if !target.AssignableTo(alias) {
+		return fmt.Errorf("alias target '%s' is not assignable to '%s'", target, alias)
+	}
+
+	// the target registration decides the lifetime so the alias never caches on its own
+	options = append(options, WithLifetime(LifetimePerRequest))
+	c.RegisterDynamic(alias, func(r Resolver) (any, error) {
+		return r.Resolve(target)
+	}, options...)
+	return nil
+}
+
+func (c *container) RegisterError(t reflect.Type, err error, options ...InstanceRegistrationOption) {
+	c.RegisterDynamic(t, func(r Resolver) (any, error) {
+		return nil, err
+	}, options...)
+}
+
+func (c *container) ReplaceDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	c.RemoveAll(t)
+	c.RegisterDynamic(t, delegate, options...)
+}
+
+func (c *container) ReplaceInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
+	c.RemoveAll(t)
+	c.RegisterInstance(t, instance, options...)
+}
+
+func (c *container) ReplaceByName(t reflect.Type, name string, instance any, options ...InstanceRegistrationOption) {
+	// registering a name again overwrites the named item, so the name is applied last
+	options = append(options, WithName(name))
+	c.registerInstance(t, instance, options...)
+}
+
+func (c *container) ReplaceDynamicByName(t reflect.Type, name string, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	// registering a name again overwrites the named item, so the name is applied last
+	options = append(options, WithName(name))
+	c.RegisterDynamic(t, delegate, options...)
+}
+
+func (c *container) RemoveAll(t reflect.Type) {
+	delete(c.groups, t)
+}
+
+func (c *container) Registrations(t reflect.Type) []RegistrationInfo {
+	group, err := c.group(t)
+	if err != nil {
+		return nil
+	}
+	var infos []RegistrationInfo
+	for _, item := range group.ordered() {
+		infos = append(infos, item.info())
+	}
+	return infos
+}
+
+func (c *container) ClearCache() {
+	c.mutex.Lock()
+	var items []*containerItem
+	for _, group := range c.groups {
+		items = append(items, group.ordered()...)
+	}
+	scoped := map[*containerItem]bool{}
+	for item := range c.scoped {
+		scoped[item] = true
+	}
+	c.scoped = nil
+	c.mutex.Unlock()
+
+	c.release(scoped)
+	c.reset(items)
+}
+
+func (c *container) SetLifetime(t reflect.Type, lifetime Lifetime) {
+	group, err := c.localGroup(t)
+	if err != nil {
+		return
+	}
+	items := group.ordered()
+	c.mutex.Lock()
+	for _, item := range items {
+		item.option.lifetime = lifetime
+	}
+	c.mutex.Unlock()
+	c.reset(items)
+}
+
+// reset clears the cached instances of items registered in this container so they are constructed again.
+// The instances are released so they are no longer listed as constructed.
+func (c *container) reset(items []*containerItem) {
+	reset := map[*containerItem]bool{}
+	c.mutex.Lock()
+	for _, item := range items {
+		item.reset()
+		delete(c.scoped, item)
+		reset[item] = true
+	}
+	c.mutex.Unlock()
+
+	// statics are tracked by the root container, scoped instances by the scope that constructed them
+	c.release(reset)
+	if root := c.root(); root != c {
+		root.release(reset)
+	}
+}
+
+func (c *container) HasDefault(t reflect.Type) bool {
+	group, err := c.group(t)
+	if err != nil {
+		return false
+	}
+	_, err = c.defaultItem(group, t)
+	return err == nil
+}
+
+func (c *container) Types() []reflect.Type {
+	types := []reflect.Type{}
+	for _, group := range c.visibleGroups() {
+		types = append(types, group.t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+	return types
+}
+
+func (c *container) Count(t reflect.Type) int {
+	group, err := c.group(t)
+	if err != nil {
+		return 0
+	}
+	return len(group.items) + len(group.namedItems) + len(group.keyedItems)
+}
+
+// pointerElementGroup returns the group of the element type if t is a pointer and the element type is registered
+func (c *container) pointerElementGroup(t reflect.Type) (*containerItemGroup, bool) {
+	if t.Kind() != reflect.Pointer {
+		return nil, false
+	}
+	group, err := c.group(t.Elem())
+	if err != nil {
+		return nil, false
+	}
+	return group, true
+}
+
+// group returns the group of the type from this container or the nearest parent that registers it
+func (c *container) group(t reflect.Type) (*containerItemGroup, error) {
+	group, err := c.localGroup(t)
+	if err != nil && c.parent != nil {
+		return c.parent.group(t)
+	}
+	return group, err
+}
+
+// visibleGroups returns the groups of this container and its parents.
+// Types registered in a scope hide the registrations of the same type in its parents.
+func (c *container) visibleGroups() []*containerItemGroup {
+	var groups []*containerItemGroup
+	seen := map[reflect.Type]struct{}{}
+	for current := c; current != nil; current = current.parent {
+		for t, group := range current.groups {
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// registered returns true if the type has a registration in this container or its parents
+func (c *container) registered(t reflect.Type) bool {
+	_, err := c.group(t)
+	return err == nil
+}
+
+// registeredName returns true if the type has a registration with the name in this container or its parents
+func (c *container) registeredName(t reflect.Type, name string) bool {
+	group, err := c.group(t)
+	if err != nil {
+		return false
+	}
+	_, ok := group.namedItems[name]
+	return ok
+}
+
+// localGroup returns the group of the type registered in this container, ignoring parents
+func (c *container) localGroup(t reflect.Type) (*containerItemGroup, error) {
+	group, ok := c.groups[t]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotExist, t.String())
+	}
+	return group, nil
+}
+
+func (c *container) Resolve(t reflect.Type) (any, error) {
+	instance, err := c.resolve(c.start(), t)
+	return instance, c.check(err)
+}
+
+// start returns the resolution that resolutions started by the Resolver methods of the container are nested in,
+// so Container fields injected while resolving receive the container
+func (c *container) start() *resolution {
+	return &resolution{
+		container: c,
+		origin:    c,
+	}
+}
+
+// check panics with the error if the container was created WithPanicOnResolveError, otherwise it returns the error
+func (c *container) check(err error) error {
+	if err != nil && c.panicOnResolveError {
+		panic(err)
+	}
+	return err
+}
+
+func (c *container) ResolveWithType(t reflect.Type) (any, reflect.Type, error) {
+	instance, err := c.Resolve(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	// a nil instance has no concrete type so fall back to the requested type
+	if instance == nil {
+		return nil, t, nil
+	}
+	return instance, reflect.TypeOf(instance), nil
+}
+
+func (c *container) ResolveWhere(t reflect.Type, predicate func(RegistrationInfo) bool) (any, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	for _, item := range group.ordered() {
+		if predicate(item.info()) {
+			instance, err := item.resolve(c, nil)
+			return instance, c.check(err)
+		}
+	}
+	return nil, c.check(fmt.Errorf("%w: no registration of '%s' matches the predicate", ErrNotExist, t.String()))
+}
+
+func (c *container) ResolveAllByLifetime(t reflect.Type, lifetime Lifetime) ([]any, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	all := []any{}
+	for _, item := range c.sliceOrder(group) {
+		if item.option.lifetime != lifetime {
+			continue
+		}
+		data, err := item.resolve(c, nil)
+		if err != nil {
+			return nil, c.check(err)
+		}
+		all = append(all, data)
+	}
+	return all, nil
+}
+
+func (c *container) ResolveAllContext(ctx context.Context, t reflect.Type) ([]any, error) {
+	items, err := c.allItems(t)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	parent := &resolution{
+		container: c,
+		ctx:       ctx,
+	}
+	var all []any
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, c.check(err)
+		}
+		data, err := item.resolve(c, parent)
+		if err != nil {
+			return nil, c.check(err)
+		}
+		all = append(all, data)
+	}
+	if c.dedupAll() {
+		all = dedup(all)
+	}
+	return all, nil
+}
+
+func (c *container) ResolveAllChan(ctx context.Context, t reflect.Type) <-chan Result {
+	results := make(chan Result)
+	items, err := c.allItems(t)
+	parent := &resolution{
+		container: c,
+		ctx:       ctx,
+	}
+	go func() {
+		defer close(results)
+		send := func(result Result) bool {
+			select {
+			case results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if err != nil {
+			send(Result{Err: err})
+			return
+		}
+		seen := identities{}
+		for _, item := range items {
+			if ctx.Err() != nil {
+				return
+			}
+			data, err := item.resolve(c, parent)
+			if err != nil {
+				send(Result{Err: err})
+				return
+			}
+			if c.dedupAll() && !seen.add(data) {
+				continue
+			}
+			if !send(Result{Value: data}) {
+				return
+			}
+		}
+	}()
+	return results
+}
+
+func (c *container) ResolveMapOrdered(t reflect.Type) ([]NamedValue, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	values := []NamedValue{}
+	for _, name := range group.names() {
+		data, err := group.namedItems[name].resolve(c, nil)
+		if err != nil {
+			return nil, c.check(err)
+		}
+		values = append(values, NamedValue{
+			Name:  name,
+			Value: data,
+		})
+	}
+	return values, nil
+}
+
+func (c *container) ResolveMapResults(t reflect.Type) (map[string]Result, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	items, err := c.mapItems(group)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	results := map[string]Result{}
+	for name, item := range items {
+		data, err := item.resolve(c, nil)
+		results[name] = Result{
+			Value: data,
+			Err:   err,
+		}
+	}
+	return results, nil
+}
+
+func (c *container) ResolveWith(t reflect.Type, overrides ...any) (any, error) {
+	instance, err := c.withOverrides(overrides).Resolve(t)
+	return instance, c.check(err)
+}
+
+func (c *container) ResolveOrConstruct(t reflect.Type, fallback FuncResolver) (any, error) {
+	instance, err := c.resolve(nil, t)
+	if err == nil {
+		return instance, nil
+	}
+
+	// a registered type that fails to resolve is an error, not a reason to fall back
+	if _, groupErr := c.group(t); groupErr == nil || !errors.Is(err, ErrNotExist) {
+		return nil, c.check(err)
+	}
+	instance, err = fallback(&resolution{container: c})
+	return instance, c.check(err)
+}
+
+func (c *container) ResolveFirst(types ...reflect.Type) (any, error) {
+	var errs []error
+	for _, t := range types {
+		instance, err := c.resolve(nil, t)
+		if err == nil {
+			return instance, nil
+		}
+		if !unregistered(c, t, err) {
+			return nil, c.check(err)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, c.check(fmt.Errorf("%w: no types given", ErrNotExist))
+	}
+	return nil, c.check(c.joinErrors(errs))
+}
+
+func (c *container) ResolveCached(t reflect.Type) (any, bool, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, false, c.check(err)
+	}
+	item, err := c.defaultItem(group, t)
+	if err != nil {
+		return nil, false, c.check(err)
+	}
+	instance, cached, err := item.resolveCached(c, nil)
+	return instance, cached, c.check(err)
+}
+
+func (c *container) ResolveByName(t reflect.Type, name string) (any, error) {
+	instance, err := c.resolveByName(c.start(), t, name)
+	return instance, c.check(err)
+}
+
+func (c *container) ResolveByKey(t reflect.Type, key any) (any, error) {
+	instance, err := c.resolveByKey(c.start(), t, key)
+	return instance, c.check(err)
+}
+
+func (c *container) ResolveKeyed(t reflect.Type) (map[any]any, error) {
+	instances, err := c.resolveKeyed(c.start(), t)
+	return instances, c.check(err)
+}
+
+func (c *container) ResolveAll(t reflect.Type) ([]any, error) {
+	instances, err := c.resolveAll(c.start(), t)
+	return instances, c.check(err)
+}
+
+func (c *container) ResolveMap(t reflect.Type) (map[string]any, error) {
+	instances, err := c.resolveMap(c.start(), t)
+	return instances, c.check(err)
+}
+
+func (c *container) resolve(parent *resolution, t reflect.Type) (any, error) {
+	if instance, ok := parent.override(t); ok {
+		return instance, nil
+	}
+	group, err := c.group(t)
+	if err != nil {
+		// a pointer can be resolved from a registration of the element type
+		if elementGroup, ok := c.pointerElementGroup(t); ok {
+			item, err := c.defaultItem(elementGroup, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			return item.resolveAddress(c, parent)
+		}
+		if c.assignable {
+			if item, ok := c.assignableItem(t); ok {
+				return item.resolve(c, parent)
+			}
+			if assignableErr := c.pointerReceiverError(t); assignableErr != nil {
+				return nil, assignableErr
+			}
+		}
+		if c.autowire && canAutowire(t) {
+			return c.autowireType(parent, t)
+		}
+		return nil, err
+	}
+	item, err := c.defaultItem(group, t)
+	if err != nil {
+		return nil, err
+	}
+	return item.resolve(c, parent)
+}
+
+func (c *container) resolveByName(parent *resolution, t reflect.Type, name string) (any, error) {
 	group, err := c.group(t)
 	if err != nil {
 		return nil, err
@@ -248,27 +1866,130 @@ func (c *container) ResolveByName(t reflect.Type, name string) (any, error) {
 	if !ok {
 		return nil, fmt.Errorf("%w: '%s'", ErrNameNotExist, name)
 	}
-	return item.resolve(c)
+	return item.resolve(c, parent)
 }
 
-func (c *container) ResolveAll(t reflect.Type) ([]any, error) {
+func (c *container) resolveByKey(parent *resolution, t reflect.Type, key any) (any, error) {
 	group, err := c.group(t)
 	if err != nil {
 		return nil, err
 	}
+	item, ok := group.keyedItems[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%v'", ErrKeyNotExist, key)
+	}
+	return item.resolve(c, parent)
+}
+
+func (c *container) resolveKeyed(parent *resolution, t reflect.Type) (map[any]any, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, err
+	}
+	result := map[any]any{}
+	for _, key := range group.keys {
+		data, err := group.keyedItems[key].resolve(c, parent)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = data
+	}
+	return result, nil
+}
 
-	// loop over the group named instances and collect
+func (c *container) resolveAll(parent *resolution, t reflect.Type) ([]any, error) {
+	items, err := c.allItems(t)
+	if err != nil {
+		return nil, err
+	}
 	var all []any
-	for _, v := range group.namedItems {
-		data, err := v.resolve(c)
+	for _, v := range items {
+		data, err := v.resolve(c, parent)
 		if err != nil {
 			return nil, err
 		}
 		all = append(all, data)
 	}
-	// loop over regular instances and collect
-	for _, v := range group.items {
-		data, err := v.resolve(c)
+	if c.dedupAll() {
+		all = dedup(all)
+	}
+	return all, nil
+}
+
+// allItems returns the items resolved by ResolveAll for the element type t,
+// the items selected by the slice options if the container has them
+func (c *container) allItems(t reflect.Type) ([]*containerItem, error) {
+	if c.sliceOptions != nil {
+		return c.sliceItems(t)
+	}
+	group, err := c.group(t)
+	if err != nil {
+		return nil, err
+	}
+	return c.sliceOrder(group), nil
+}
+
+// dedupAll returns true if ResolveAll removes repeated pointer like instances
+func (c *container) dedupAll() bool {
+	return c.dedup || (c.sliceOptions != nil && c.sliceOptions.Dedup)
+}
+
+// identity is the key used to compare pointer like values by what they point to
+type identity struct {
+	t       reflect.Type
+	pointer uintptr
+}
+
+// identities holds the identities of the pointer like values seen so far
+type identities map[identity]struct{}
+
+// add returns false if the value is pointer like and a value pointing to the same thing was added before
+func (s identities) add(value any) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		key := identity{t: v.Type(), pointer: v.Pointer()}
+		if _, ok := s[key]; ok {
+			return false
+		}
+		s[key] = struct{}{}
+	}
+	return true
+}
+
+// dedup removes repeated pointer like values, keeping the first occurrence. Other values are kept as is.
+func dedup(values []any) []any {
+	seen := identities{}
+	var result []any
+	for _, value := range values {
+		if seen.add(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func (c *container) resolveRest(t reflect.Type) ([]any, error) {
+	instances, err := c.resolveRestFrom(c.start(), t)
+	return instances, c.check(err)
+}
+
+// resolveRestFrom resolves every item of the type except the default item
+func (c *container) resolveRestFrom(parent *resolution, t reflect.Type) ([]any, error) {
+	group, err := c.group(t)
+	if err != nil {
+		return nil, err
+	}
+	defaultItem, err := c.defaultItem(group, t)
+	if err != nil {
+		return nil, err
+	}
+	all := []any{}
+	for _, item := range c.sliceOrder(group) {
+		if item == defaultItem {
+			continue
+		}
+		data, err := item.resolve(c, parent)
 		if err != nil {
 			return nil, err
 		}
@@ -277,15 +1998,19 @@ func (c *container) ResolveAll(t reflect.Type) ([]any, error) {
 	return all, nil
 }
 
-func (c *container) ResolveMap(t reflect.Type) (map[string]any, error) {
+func (c *container) resolveMap(parent *resolution, t reflect.Type) (map[string]any, error) {
 	group, err := c.group(t)
 	if err != nil {
 		return nil, err
 	}
 
+	items, err := c.mapItems(group)
+	if err != nil {
+		return nil, err
+	}
 	result := map[string]any{}
-	for k, v := range group.namedItems {
-		data, err := v.resolve(c)
+	for k, v := range items {
+		data, err := v.resolve(c, parent)
 		if err != nil {
 			return nil, err
 		}
@@ -293,3 +2018,31 @@ func (c *container) ResolveMap(t reflect.Type) (map[string]any, error) {
 	}
 	return result, nil
 }
+
+// mapItems returns a snapshot of the items of the group that are resolved into a map by their map key,
+// so registrations made while the items resolve do not change the map being resolved
+func (c *container) mapItems(group *containerItemGroup) (map[string]*containerItem, error) {
+	if c.mapKeyMetadata == "" {
+		items := make(map[string]*containerItem, len(group.namedItems))
+		for name, item := range group.namedItems {
+			items[name] = item
+		}
+		return items, nil
+	}
+	items := map[string]*containerItem{}
+	for _, item := range group.ordered() {
+		value, ok := item.option.metadata[c.mapKeyMetadata]
+		if !ok {
+			if item.option.name != "" {
+				items[item.option.name] = item
+			}
+			continue
+		}
+		key, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("metadata '%s' of '%s' must be a string to be used as a map key", c.mapKeyMetadata, item.option.key)
+		}
+		items[key] = item
+	}
+	return items, nil
+}
@@ -1,11 +1,19 @@
 package di_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/patrickhuber/go-di"
+	first "github.com/patrickhuber/go-di/internal/first/config"
+	second "github.com/patrickhuber/go-di/internal/second/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,6 +37,33 @@ type SampleInterface interface {
 	Name() string
 }
 
+// Event is passed to handler functions
+type Event struct {
+	Name string
+}
+
+type EventHandler func(Event) error
+
+var EventHandlerType = reflect.TypeOf((*EventHandler)(nil)).Elem()
+
+// RunnerSample is a broader interface than SampleInterface
+type RunnerSample interface {
+	SampleInterface
+	Run()
+}
+
+type runnerSample struct {
+	name string
+}
+
+func (r *runnerSample) Name() string {
+	return r.name
+}
+
+func (r *runnerSample) Run() {}
+
+var RunnerSampleType = reflect.TypeOf((*RunnerSample)(nil)).Elem()
+
 func NewSample(name string) SampleInterface {
 	return &SampleStruct{
 		name: name,
@@ -169,6 +204,239 @@ func TestContainer(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, name, value)
 	})
+	t.Run("resolve with type", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("test"))
+		instance, concrete, err := container.ResolveWithType(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotNil(t, instance)
+		require.Equal(t, reflect.TypeOf(&SampleStruct{}), concrete)
+		require.NotEqual(t, SampleInterfaceType, concrete)
+	})
+	t.Run("resolve with type missing", func(t *testing.T) {
+		container := di.NewContainer()
+		_, _, err := container.ResolveWithType(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("register error", func(t *testing.T) {
+		container := di.NewContainer()
+		expected := errors.New("expected")
+		container.RegisterError(SampleInterfaceType, expected)
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.Nil(t, instance)
+		require.True(t, errors.Is(err, expected))
+	})
+	t.Run("pointer from value static", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(ChildType, Child{Something: "value"})
+		childPointerType := reflect.PointerTo(ChildType)
+
+		first, err := container.Resolve(childPointerType)
+		require.NoError(t, err)
+		child, ok := first.(*Child)
+		require.True(t, ok)
+		require.Equal(t, "value", child.Something)
+
+		second, err := container.Resolve(childPointerType)
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		// changes through the pointer do not change the registered value
+		child.Something = "changed"
+		value, err := container.Resolve(ChildType)
+		require.NoError(t, err)
+		require.Equal(t, "value", value.(Child).Something)
+	})
+	t.Run("pointer from value per request", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(ChildType, Child{Something: "value"}, di.WithLifetime(di.LifetimePerRequest))
+
+		result, err := di.Invoke(container, func(first *Child, second *Child) bool {
+			return first != second && first.Something == second.Something
+		})
+		require.NoError(t, err)
+		require.Equal(t, true, result)
+	})
+	t.Run("register named instances", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterNamedInstances(SampleInterfaceType, map[string]any{
+			"one":   NewSample("one"),
+			"two":   NewSample("two"),
+			"three": NewSample("three"),
+		})
+		for _, name := range []string{"one", "two", "three"} {
+			instance, err := container.ResolveByName(SampleInterfaceType, name)
+			require.NoError(t, err)
+			require.Equal(t, name, instance.(SampleInterface).Name())
+		}
+		instances, err := container.ResolveMap(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(instances))
+		for name, instance := range instances {
+			require.Equal(t, name, instance.(SampleInterface).Name())
+		}
+	})
+	t.Run("on resolve", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		var observed []reflect.Type
+		container.OnResolve(func(t reflect.Type, instance any) (any, error) {
+			observed = append(observed, t)
+			return instance, nil
+		})
+		container.OnResolve(func(t reflect.Type, instance any) (any, error) {
+			if s, ok := instance.(string); ok {
+				return s + " wrapped", nil
+			}
+			return instance, nil
+		})
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test wrapped", instance.(SampleInterface).Name())
+		require.Equal(t, []reflect.Type{StringType, SampleInterfaceType}, observed)
+
+		value, err := container.Resolve(StringType)
+		require.NoError(t, err)
+		require.Equal(t, "test wrapped", value)
+		require.Equal(t, 3, len(observed))
+	})
+	t.Run("on resolve error", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		expected := errors.New("expected")
+		container.OnResolve(func(t reflect.Type, instance any) (any, error) {
+			return nil, expected
+		})
+		_, err := container.Resolve(StringType)
+		require.ErrorIs(t, err, expected)
+
+		scope := container.Scope()
+		_, err = scope.Resolve(StringType)
+		require.ErrorIs(t, err, expected)
+	})
+	t.Run("max resolve depth", func(t *testing.T) {
+		newContainer := func(depth int) di.Container {
+			container := di.NewContainer(di.WithMaxResolveDepth(depth))
+			container.RegisterInstance(StringType, "test")
+			require.NoError(t, container.RegisterConstructor(NewSample))
+			require.NoError(t, container.RegisterConstructor(func(s SampleInterface) DependencyInterface {
+				return s
+			}))
+			return container
+		}
+		instance, err := newContainer(3).Resolve(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(DependencyInterface).Name())
+
+		_, err = newContainer(2).Resolve(DependencyInterfaceType)
+		require.ErrorIs(t, err, di.ErrMaxDepth)
+		require.Contains(t, err.Error(), "depth 3 of 'string' exceeds 2")
+		require.Contains(t, err.Error(), "di_test.DependencyInterface -> di_test.SampleInterface -> string")
+	})
+	t.Run("registration during resolve all", func(t *testing.T) {
+		container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			container.RegisterInstance(SampleInterfaceType, NewSample("discovered"))
+			return NewSample("two"), nil
+		})
+		container.RegisterInstance(SampleInterfaceType, NewSample("three"))
+
+		names := func() []string {
+			instances, err := container.ResolveAll(SampleInterfaceType)
+			require.NoError(t, err)
+			var names []string
+			for _, instance := range instances {
+				names = append(names, instance.(SampleInterface).Name())
+			}
+			return names
+		}
+		require.Equal(t, []string{"one", "two", "three"}, names())
+		require.Equal(t, []string{"one", "two", "three", "discovered"}, names())
+	})
+	t.Run("registration during resolve map", func(t *testing.T) {
+		container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+		discovered := 0
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			discovered++
+			container.RegisterInstance(SampleInterfaceType, NewSample("discovered"), di.WithName(fmt.Sprintf("discovered %d", discovered)))
+			return NewSample("plugin"), nil
+		}, di.WithName("plugin"))
+
+		instances, err := container.ResolveMap(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(instances))
+
+		instances, err = container.ResolveMap(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(instances))
+	})
+	t.Run("self resolution", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(StringType, func(r di.Resolver) (any, error) {
+			return r.Resolve(StringType)
+		})
+		_, err := container.Resolve(StringType)
+		require.Error(t, err)
+		require.ErrorIs(t, err, di.ErrCircular)
+	})
+	t.Run("circular constructors", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func(s SampleInterface) string {
+			return s.Name()
+		})
+		require.NoError(t, err)
+		err = container.RegisterConstructor(NewSample)
+		require.NoError(t, err)
+
+		_, err = container.Resolve(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrCircular)
+	})
+}
+
+func TestFromMap(t *testing.T) {
+	container := di.FromMap(map[reflect.Type]any{
+		StringType:          "test",
+		SampleInterfaceType: NewSample("sample"),
+		StorageType:         NewStorage(),
+	})
+
+	value, err := container.Resolve(StringType)
+	require.NoError(t, err)
+	require.Equal(t, "test", value)
+
+	sample, err := container.Resolve(SampleInterfaceType)
+	require.NoError(t, err)
+	require.Equal(t, "sample", sample.(SampleInterface).Name())
+
+	storage, err := container.Resolve(StorageType)
+	require.NoError(t, err)
+	require.NotNil(t, storage)
+}
+
+func TestValidateConstructor(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		require.NoError(t, di.ValidateConstructor(NewSample))
+	})
+	t.Run("value and error", func(t *testing.T) {
+		require.NoError(t, di.ValidateConstructor(func() (SampleInterface, error) {
+			return nil, nil
+		}))
+	})
+	t.Run("two values", func(t *testing.T) {
+		require.Error(t, di.ValidateConstructor(func() (SampleInterface, string) {
+			return nil, ""
+		}))
+	})
+	t.Run("no return", func(t *testing.T) {
+		require.Error(t, di.ValidateConstructor(func() {}))
+	})
+	t.Run("not a function", func(t *testing.T) {
+		require.Error(t, di.ValidateConstructor("test"))
+		require.Error(t, di.ValidateConstructor(nil))
+	})
 }
 
 func TestConstructor(t *testing.T) {
@@ -188,6 +456,101 @@ func TestConstructor(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, name, sample.Name())
 	})
+	t.Run("also value type", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func() *SampleStruct {
+			return &SampleStruct{name: "test"}
+		}, di.WithAlsoValueType())
+		require.NoError(t, err)
+
+		pointer, err := container.Resolve(reflect.TypeOf(&SampleStruct{}))
+		require.NoError(t, err)
+		require.Equal(t, "test", pointer.(*SampleStruct).Name())
+
+		value, err := container.Resolve(reflect.TypeOf(SampleStruct{}))
+		require.NoError(t, err)
+		sample := value.(SampleStruct)
+		require.Equal(t, "test", sample.Name())
+
+		err = container.RegisterConstructor(NewSample, di.WithAlsoValueType())
+		require.Error(t, err)
+	})
+	t.Run("optional collections", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		err := container.RegisterConstructor(func(name string, dependencies []DependencyInterface, named map[string]DependencyInterface) SampleInterface {
+			return NewSample(fmt.Sprintf("%s %d %d", name, len(dependencies), len(named)))
+		}, di.WithOptionalCollections())
+		require.NoError(t, err)
+		require.NoError(t, container.RegisterConstructor(NewAggregate))
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test 0 0", instance.(SampleInterface).Name())
+
+		_, err = container.Resolve(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("optional collections captured", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(NewAggregate,
+			di.WithOptionalCollections(),
+			di.WithCaptureCollections())
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(instance.(AggregateInterface).Names()))
+	})
+	t.Run("register constructor as", func(t *testing.T) {
+		type test struct {
+			name     string
+			lifetime di.Lifetime
+			same     bool
+		}
+		tests := []test{
+			{"static", di.LifetimeStatic, true},
+			{"per request", di.LifetimePerRequest, false},
+		}
+		for _, test := range tests {
+			test := test
+			t.Run(test.name, func(t *testing.T) {
+				var closed []string
+				constructed := 0
+				container := di.NewContainer()
+				err := container.RegisterConstructorAs(func() *closer {
+					constructed++
+					return &closer{name: "service", closed: &closed}
+				}, []reflect.Type{CloserType, reflect.TypeOf((*io.Closer)(nil)).Elem()}, di.WithLifetime(test.lifetime))
+				require.NoError(t, err)
+
+				first, err := container.Resolve(CloserType)
+				require.NoError(t, err)
+				second, err := container.Resolve(reflect.TypeOf((*io.Closer)(nil)).Elem())
+				require.NoError(t, err)
+				if test.same {
+					require.Same(t, first, second)
+					require.Equal(t, 1, constructed)
+				} else {
+					require.NotSame(t, first, second)
+					require.Equal(t, 2, constructed)
+				}
+				// per request instances are not closed by the container, static instances are closed once
+				require.NoError(t, container.Close())
+				if test.same {
+					require.Equal(t, []string{"service"}, closed)
+				} else {
+					require.Empty(t, closed)
+				}
+			})
+		}
+	})
+	t.Run("register constructor as requires assignable", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructorAs(NewSample, []reflect.Type{SampleInterfaceType, CloserType})
+		require.Error(t, err)
+		require.Equal(t, 0, container.Count(SampleInterfaceType))
+	})
 	t.Run("array parameter", func(t *testing.T) {
 		container := di.NewContainer()
 		dependencies := []*SampleStruct{
@@ -224,6 +587,72 @@ func TestConstructor(t *testing.T) {
 		_, ok := instance.(AggregateInterface)
 		require.True(t, ok)
 	})
+	t.Run("required and variadic of same type", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two", "three"} {
+			container.RegisterInstance(DependencyInterfaceType, NewSample(name))
+		}
+		err := container.RegisterConstructor(func(first DependencyInterface, rest ...DependencyInterface) AggregateInterface {
+			names := []string{first.Name()}
+			for _, d := range rest {
+				names = append(names, d.Name())
+			}
+			return &AggregateStruct{names: names}
+		})
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "two", "three"}, instance.(AggregateInterface).Names())
+	})
+	t.Run("required and variadic of same type single registration", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("one"))
+		err := container.RegisterConstructor(func(first DependencyInterface, rest ...DependencyInterface) AggregateInterface {
+			require.Equal(t, 0, len(rest))
+			return &AggregateStruct{names: []string{first.Name()}}
+		})
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"one"}, instance.(AggregateInterface).Names())
+	})
+	t.Run("variadic empty", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(NewVariadic)
+		require.NoError(t, err)
+		require.NoError(t, container.Validate())
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		aggregate, ok := instance.(AggregateInterface)
+		require.True(t, ok)
+		require.Equal(t, 0, len(aggregate.Names()))
+	})
+	t.Run("variadic element missing dependency fails", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) DependencyInterface {
+			return &SampleStruct{name: s.Name()}
+		}))
+		require.NoError(t, container.RegisterConstructor(NewVariadic))
+
+		_, err := container.Resolve(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.ErrorContains(t, err, SampleInterfaceType.String())
+
+		_, err = container.Explain(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Error(t, container.Validate())
+	})
+	t.Run("array parameter empty fails", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(NewAggregate)
+		require.NoError(t, err)
+
+		_, err = container.Resolve(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
 	t.Run("func", func(t *testing.T) {
 		container := di.NewContainer()
 		dependencies := []*SampleStruct{
@@ -264,6 +693,50 @@ func TestConstructor(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, 0, len(mapInstance.Keys()))
 	})
+	t.Run("capture collections", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("one"))
+		err := container.RegisterConstructor(NewAggregate,
+			di.WithLifetime(di.LifetimePerRequest),
+			di.WithCaptureCollections())
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(instance.(AggregateInterface).Names()))
+
+		container.RegisterInstance(DependencyInterfaceType, NewSample("two"))
+
+		instance, err = container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(instance.(AggregateInterface).Names()))
+	})
+	t.Run("capture collections error", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(NewAggregate, di.WithCaptureCollections())
+		require.NoError(t, err)
+
+		_, err = container.Resolve(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), "unable to construct '"+AggregateInterfaceType.String()+"'")
+	})
+	t.Run("without capture collections", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("one"))
+		err := container.RegisterConstructor(NewAggregate,
+			di.WithLifetime(di.LifetimePerRequest))
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(instance.(AggregateInterface).Names()))
+
+		container.RegisterInstance(DependencyInterfaceType, NewSample("two"))
+
+		instance, err = container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(instance.(AggregateInterface).Names()))
+	})
 	t.Run("err ret", func(t *testing.T) {
 		container := di.NewContainer()
 
@@ -298,6 +771,29 @@ func TestConstructor(t *testing.T) {
 		err := container.RegisterConstructor(func() {})
 		require.NotNil(t, err)
 	})
+	t.Run("register as", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func() *SampleStruct {
+			return &SampleStruct{name: "test"}
+		}, di.WithRegisterAs(SampleInterfaceType))
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		sample, ok := instance.(SampleInterface)
+		require.True(t, ok)
+		require.Equal(t, "test", sample.Name())
+
+		_, err = container.Resolve(reflect.TypeOf(&SampleStruct{}))
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("register as not assignable", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func() string {
+			return "test"
+		}, di.WithRegisterAs(SampleInterfaceType))
+		require.Error(t, err)
+	})
 	t.Run("resolve all", func(t *testing.T) {
 		container := di.NewContainer()
 		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
@@ -306,6 +802,24 @@ func TestConstructor(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 2, len(all))
 	})
+	t.Run("resolve all dedup", func(t *testing.T) {
+		sample := NewSample("one")
+
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, sample, di.WithName("one"))
+		container.RegisterInstance(SampleInterfaceType, sample)
+		all, err := container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+
+		container = di.NewContainer(di.WithResolveAllDedup())
+		container.RegisterInstance(SampleInterfaceType, sample, di.WithName("one"))
+		container.RegisterInstance(SampleInterfaceType, sample)
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"))
+		all, err = container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+	})
 	t.Run("resolve map", func(t *testing.T) {
 		container := di.NewContainer()
 		keys := []string{"one", "two"}
@@ -316,51 +830,587 @@ func TestConstructor(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, len(keys), len(m))
 	})
-	t.Run("resolve by key", func(t *testing.T) {
+	t.Run("resolve prefers unnamed", func(t *testing.T) {
 		container := di.NewContainer()
-		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
-		container.RegisterInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
-		instance, err := container.ResolveByName(SampleInterfaceType, "two")
+		container.RegisterInstance(SampleInterfaceType, NewSample("named"), di.WithName("named"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("unnamed"))
+		instance, err := container.Resolve(SampleInterfaceType)
 		require.NoError(t, err)
-		require.NotNil(t, instance)
+		require.Equal(t, "unnamed", instance.(SampleInterface).Name())
 	})
-	t.Run("lifetime", func(t *testing.T) {
-		type test struct {
-			name      string
-			container di.Container
+	t.Run("instance lifetimes", func(t *testing.T) {
+		var closed []string
+		instance := &closer{name: "instance", closed: &closed}
+		container := di.NewContainer()
+		container.RegisterInstance(CloserType, instance, di.WithName("static"))
+		container.RegisterInstance(CloserType, instance, di.WithName("transient"), di.WithLifetime(di.LifetimePerRequest))
+		for _, name := range []string{"static", "transient", "static", "transient"} {
+			resolved, err := container.ResolveByName(CloserType, name)
+			require.NoError(t, err)
+			require.Same(t, instance, resolved)
 		}
-		tests := []test{
-			{"can register default lifetime",
-				func() di.Container {
-					container := di.NewContainer(di.WithDefaultLifetime(di.LifetimeStatic))
-					err := container.RegisterConstructor(NewStorage)
-					require.NoError(t, err)
-					return container
-				}()},
-			{"can register lifetime",
-				func() di.Container {
-					container := di.NewContainer()
-					err := container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic))
-					require.NoError(t, err)
-					return container
-				}()},
-			{"can override default lifetime", func() di.Container {
-				container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
-				err := container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic))
-				require.NoError(t, err)
-				return container
-			}()},
+		require.Equal(t, 1, len(container.ConstructedInstances()))
+		require.NoError(t, container.Close())
+		require.Equal(t, []string{"instance"}, closed)
+	})
+	t.Run("function values", func(t *testing.T) {
+		container := di.NewContainer()
+		var handled []string
+		handler := func(name string) EventHandler {
+			return func(e Event) error {
+				handled = append(handled, name+":"+e.Name)
+				return nil
+			}
 		}
-		for _, test := range tests {
-			obj, err := test.container.Resolve(StorageType)
-			require.NoError(t, err)
+		container.RegisterInstance(EventHandlerType, handler("one"))
+		container.RegisterInstance(EventHandlerType, handler("two"), di.WithAsValue())
+		require.NoError(t, container.RegisterConstructor(handler("three"), di.WithAsValue()))
 
-			storage, ok := obj.(Storage)
-			require.True(t, ok)
-			storage.Set(1, "test")
-
-			obj, err = test.container.Resolve(StorageType)
-			require.NoError(t, err)
+		all, err := container.ResolveAll(EventHandlerType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(all))
+
+		_, err = di.Invoke(container, func(handlers []EventHandler) error {
+			for _, h := range handlers {
+				if err := h(Event{Name: "event"}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"one:event", "two:event", "three:event"}, handled)
+	})
+	t.Run("function value without return", func(t *testing.T) {
+		container := di.NewContainer()
+		called := false
+		fn := func() { called = true }
+		require.Error(t, container.RegisterConstructor(fn))
+		require.NoError(t, container.RegisterConstructor(fn, di.WithAsValue()))
+
+		instance, err := container.Resolve(reflect.TypeOf(fn))
+		require.NoError(t, err)
+		instance.(func())()
+		require.True(t, called)
+	})
+	t.Run("typed nil", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			var sample *SampleStruct
+			return sample, nil
+		})
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+
+		// require.NotNil treats a typed nil as nil so compare the interface directly
+		require.True(t, instance != nil)
+		sample, ok := instance.(SampleInterface)
+		require.True(t, ok)
+		require.Nil(t, sample.(*SampleStruct))
+
+		result, err := di.Invoke(container, func(s SampleInterface) bool {
+			return s != nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, true, result)
+	})
+	t.Run("typed nil from constructor", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() SampleInterface {
+			var sample *SampleStruct
+			return sample
+		}))
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.True(t, instance != nil)
+		require.Nil(t, instance.(*SampleStruct))
+	})
+	t.Run("nil", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() SampleInterface {
+			return nil
+		}))
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Nil(t, instance)
+	})
+	t.Run("resolve all chan", func(t *testing.T) {
+		container := di.NewContainer()
+		names := []string{"one", "two", "three"}
+		for _, name := range names {
+			container.RegisterInstance(DependencyInterfaceType, NewSample(name))
+		}
+		var received []string
+		for result := range container.ResolveAllChan(context.Background(), DependencyInterfaceType) {
+			require.NoError(t, result.Err)
+			received = append(received, result.Value.(DependencyInterface).Name())
+		}
+		require.Equal(t, names, received)
+	})
+	t.Run("resolve all chan dedup", func(t *testing.T) {
+		sample := NewSample("one")
+		container := di.NewContainer(di.WithResolveAllDedup())
+		container.RegisterInstance(DependencyInterfaceType, sample, di.WithName("one"))
+		container.RegisterInstance(DependencyInterfaceType, sample)
+		count := 0
+		for result := range container.ResolveAllChan(context.Background(), DependencyInterfaceType) {
+			require.NoError(t, result.Err)
+			count++
+		}
+		require.Equal(t, 1, count)
+	})
+	t.Run("resolve all chan stops on error", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("one"))
+		expected := errors.New("failed")
+		container.RegisterError(DependencyInterfaceType, expected)
+		container.RegisterInstance(DependencyInterfaceType, NewSample("three"))
+		var results []di.Result
+		for result := range container.ResolveAllChan(context.Background(), DependencyInterfaceType) {
+			results = append(results, result)
+		}
+		require.Equal(t, 2, len(results))
+		require.NoError(t, results[0].Err)
+		require.ErrorIs(t, results[1].Err, expected)
+
+		results = nil
+		for result := range container.ResolveAllChan(context.Background(), StringType) {
+			results = append(results, result)
+		}
+		require.Equal(t, 1, len(results))
+		require.ErrorIs(t, results[0].Err, di.ErrNotExist)
+	})
+	t.Run("resolve all chan canceled", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two", "three"} {
+			container.RegisterInstance(DependencyInterfaceType, NewSample(name))
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		results := container.ResolveAllChan(ctx, DependencyInterfaceType)
+		first := <-results
+		require.NoError(t, first.Err)
+		cancel()
+
+		// the channel is closed once the producer sees the cancellation, at most one more result is received
+		count := 0
+		for range results {
+			count++
+		}
+		require.LessOrEqual(t, count, 1)
+	})
+	t.Run("resolve map honors lifetimes", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(DependencyInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("static"), nil
+		}, di.WithName("static"), di.WithLifetime(di.LifetimeStatic))
+		container.RegisterDynamic(DependencyInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("transient"), nil
+		}, di.WithName("transient"), di.WithLifetime(di.LifetimePerRequest))
+
+		first, err := container.ResolveMap(DependencyInterfaceType)
+		require.NoError(t, err)
+		second, err := container.ResolveMap(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, first["static"], second["static"])
+		require.NotSame(t, first["transient"], second["transient"])
+
+		resolveMap := func(m map[string]DependencyInterface) map[string]DependencyInterface {
+			return m
+		}
+		injected, err := di.Invoke(container, resolveMap)
+		require.NoError(t, err)
+		m := injected.(map[string]DependencyInterface)
+		require.Same(t, first["static"], m["static"])
+		require.NotSame(t, first["transient"], m["transient"])
+
+		all, err := container.ResolveAll(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+		require.Same(t, first["static"], all[0])
+		require.NotSame(t, first["transient"], all[1])
+	})
+	t.Run("resolve all by lifetime", func(t *testing.T) {
+		container := di.NewContainer()
+		lifetimes := map[string]di.Lifetime{
+			"one":   di.LifetimeStatic,
+			"two":   di.LifetimePerRequest,
+			"three": di.LifetimeStatic,
+			"four":  di.LifetimeScoped,
+		}
+		for _, name := range []string{"one", "two", "three", "four"} {
+			name := name
+			container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+				return NewSample(name), nil
+			}, di.WithLifetime(lifetimes[name]))
+		}
+
+		instances, err := container.ResolveAllByLifetime(SampleInterfaceType, di.LifetimeStatic)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(instances))
+		require.Equal(t, "one", instances[0].(SampleInterface).Name())
+		require.Equal(t, "three", instances[1].(SampleInterface).Name())
+		require.Equal(t, 2, len(container.ConstructedInstances()))
+
+		_, err = container.ResolveAllByLifetime(StringType, di.LifetimeStatic)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("resolve map ordered", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, key := range []string{"charlie", "alpha", "bravo"} {
+			container.RegisterInstance(SampleInterfaceType, NewSample(key), di.WithName(key))
+		}
+		container.RegisterInstance(SampleInterfaceType, NewSample("unnamed"))
+
+		values, err := container.ResolveMapOrdered(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(values))
+		for i, name := range []string{"alpha", "bravo", "charlie"} {
+			require.Equal(t, name, values[i].Name)
+			require.Equal(t, name, values[i].Value.(SampleInterface).Name())
+		}
+	})
+	t.Run("resolve map results", func(t *testing.T) {
+		container := di.NewContainer()
+		expected := errors.New("expected")
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		container.RegisterError(SampleInterfaceType, expected, di.WithName("two"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("three"), di.WithName("three"))
+
+		results, err := container.ResolveMapResults(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(results))
+		for _, name := range []string{"one", "three"} {
+			require.NoError(t, results[name].Err)
+			require.Equal(t, name, results[name].Value.(SampleInterface).Name())
+		}
+		require.ErrorIs(t, results["two"].Err, expected)
+		require.Nil(t, results["two"].Value)
+
+		_, err = container.ResolveMapResults(StringType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("resolve by key", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
+		instance, err := container.ResolveByName(SampleInterfaceType, "two")
+		require.NoError(t, err)
+		require.NotNil(t, instance)
+	})
+	t.Run("name prefix", func(t *testing.T) {
+		container := di.NewContainer(di.WithNamePrefix("tenant."))
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
+
+		instance, err := container.ResolveByName(SampleInterfaceType, "tenant.two")
+		require.NoError(t, err)
+		require.Equal(t, "two", instance.(SampleInterface).Name())
+
+		_, err = container.ResolveByName(SampleInterfaceType, "two")
+		require.ErrorIs(t, err, di.ErrNameNotExist)
+
+		m, err := container.ResolveMap(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Contains(t, m, "tenant.one")
+	})
+	t.Run("same named types", func(t *testing.T) {
+		firstType := reflect.TypeOf(first.Config{})
+		secondType := reflect.TypeOf(second.Config{})
+		require.Equal(t, firstType.String(), secondType.String())
+
+		container := di.NewContainer()
+		container.RegisterInstance(firstType, first.Config{Name: "first"})
+		container.RegisterInstance(secondType, second.Config{Name: "second"})
+
+		instance, err := container.Resolve(firstType)
+		require.NoError(t, err)
+		require.Equal(t, "first", instance.(first.Config).Name)
+
+		instance, err = container.Resolve(secondType)
+		require.NoError(t, err)
+		require.Equal(t, "second", instance.(second.Config).Name)
+
+		container.RemoveAll(firstType)
+		require.Equal(t, 0, container.Count(firstType))
+		require.Equal(t, 1, container.Count(secondType))
+	})
+	t.Run("register spec", func(t *testing.T) {
+		container := di.NewContainer()
+		perRequest := di.LifetimePerRequest
+		specs := []di.RegistrationSpec{
+			{Type: StringType, Instance: "instance"},
+			{Constructor: NewSample, Name: "constructor", Lifetime: &perRequest, Metadata: map[string]any{"source": "config"}},
+			{Type: DependencyInterfaceType, Key: "dynamic", Dynamic: func(r di.Resolver) (any, error) {
+				return NewSample("dynamic"), nil
+			}},
+		}
+		for _, spec := range specs {
+			require.NoError(t, container.RegisterSpec(spec))
+		}
+
+		instance, err := container.Resolve(StringType)
+		require.NoError(t, err)
+		require.Equal(t, "instance", instance)
+
+		instance, err = container.ResolveByName(SampleInterfaceType, "constructor")
+		require.NoError(t, err)
+		require.Equal(t, "instance", instance.(SampleInterface).Name())
+		info := container.Registrations(SampleInterfaceType)[0]
+		require.Equal(t, di.LifetimePerRequest, info.Lifetime)
+		require.Equal(t, "config", info.Metadata["source"])
+
+		instance, err = container.ResolveByKey(DependencyInterfaceType, "dynamic")
+		require.NoError(t, err)
+		require.Equal(t, "dynamic", instance.(DependencyInterface).Name())
+	})
+	t.Run("register spec default lifetime", func(t *testing.T) {
+		container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+		require.NoError(t, container.RegisterSpec(di.RegistrationSpec{Type: StringType, Instance: "default"}))
+		static := di.LifetimeStatic
+		require.NoError(t, container.RegisterSpec(di.RegistrationSpec{Type: StringType, Instance: "static", Name: "static", Lifetime: &static}))
+
+		infos := container.Registrations(StringType)
+		require.Equal(t, di.LifetimePerRequest, infos[0].Lifetime)
+		require.Equal(t, di.LifetimeStatic, infos[1].Lifetime)
+	})
+	t.Run("register spec invalid", func(t *testing.T) {
+		container := di.NewContainer()
+		require.Error(t, container.RegisterSpec(di.RegistrationSpec{Instance: "missing type"}))
+		require.Error(t, container.RegisterSpec(di.RegistrationSpec{Type: StringType, Instance: "test", Constructor: NewSample}))
+		require.Error(t, container.RegisterSpec(di.RegistrationSpec{Constructor: "not a function"}))
+		require.Error(t, container.RegisterSpec(di.RegistrationSpec{Type: StringType, Name: "empty"}))
+		require.Equal(t, 0, container.Count(StringType))
+	})
+	t.Run("named type", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "hello")
+		container.RegisterNamedType("greeter", SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return di.Invoke(r, NewSample)
+		})
+
+		instance, err := container.ResolveNamedType("greeter")
+		require.NoError(t, err)
+		require.Equal(t, "hello", instance.(SampleInterface).Name())
+
+		_, err = container.ResolveNamedType("missing")
+		require.ErrorIs(t, err, di.ErrNotExist)
+
+		instance, err = container.Scope().ResolveNamedType("greeter")
+		require.NoError(t, err)
+		require.Equal(t, "hello", instance.(SampleInterface).Name())
+	})
+	t.Run("alias", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(RunnerSampleType, func(r di.Resolver) (any, error) {
+			return &runnerSample{name: "test"}, nil
+		}, di.WithLifetime(di.LifetimeStatic))
+		require.NoError(t, container.RegisterAlias(SampleInterfaceType, RunnerSampleType))
+
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+
+		target, err := container.Resolve(RunnerSampleType)
+		require.NoError(t, err)
+		require.Same(t, target, instance)
+	})
+	t.Run("alias not assignable", func(t *testing.T) {
+		container := di.NewContainer()
+		require.Error(t, container.RegisterAlias(RunnerSampleType, SampleInterfaceType))
+	})
+	t.Run("clear cache", func(t *testing.T) {
+		container := di.NewContainer()
+		var constructed []string
+		finalized := 0
+		for _, name := range []string{"one", "two"} {
+			name := name
+			container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+				constructed = append(constructed, name)
+				return NewSample(name), nil
+			}, di.WithName(name), di.WithLifetime(di.LifetimeStatic), di.WithFinalizer(func(any) error {
+				finalized++
+				return nil
+			}))
+		}
+		first, err := container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		_, err = container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "two"}, constructed)
+
+		container.ClearCache()
+		require.Empty(t, container.ConstructedInstances())
+		second, err := container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "two", "one", "two"}, constructed)
+		require.NotSame(t, first[0], second[0])
+		require.Equal(t, 2, container.Count(SampleInterfaceType))
+		require.Equal(t, second, container.ConstructedInstances())
+
+		// the cleared instances are still disposed with the container
+		require.NoError(t, container.Close())
+		require.Equal(t, 4, finalized)
+	})
+	t.Run("set lifetime", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("test"), nil
+		}, di.WithLifetime(di.LifetimePerRequest))
+
+		first, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		second, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotSame(t, first, second)
+
+		container.SetLifetime(SampleInterfaceType, di.LifetimeStatic)
+		first, err = container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		second, err = container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		require.Equal(t, []any{first}, container.ConstructedInstances())
+
+		container.SetLifetime(SampleInterfaceType, di.LifetimePerRequest)
+		require.Empty(t, container.ConstructedInstances())
+		third, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotSame(t, first, third)
+
+		container.SetLifetime(SampleInterfaceType, di.LifetimeStatic)
+		fourth, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotSame(t, first, fourth)
+		require.Equal(t, di.LifetimeStatic, container.Registrations(SampleInterfaceType)[0].Lifetime)
+	})
+	t.Run("enabled", func(t *testing.T) {
+		container := di.NewContainer()
+		evaluated := 0
+		container.RegisterInstance(SampleInterfaceType, NewSample("enabled"), di.WithEnabled(func() bool {
+			evaluated++
+			return true
+		}))
+		container.RegisterInstance(SampleInterfaceType, NewSample("disabled"), di.WithEnabled(func() bool {
+			evaluated++
+			return false
+		}))
+
+		all, err := container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(all))
+		require.Equal(t, "enabled", all[0].(SampleInterface).Name())
+
+		_, err = container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, evaluated)
+	})
+	t.Run("has default", func(t *testing.T) {
+		container := di.NewContainer()
+		require.False(t, container.HasDefault(SampleInterfaceType))
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		require.True(t, container.HasDefault(SampleInterfaceType))
+	})
+	t.Run("has default without named fallback", func(t *testing.T) {
+		container := di.NewContainer(di.WithoutNamedFallback())
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		require.False(t, container.HasDefault(SampleInterfaceType))
+		_, err := container.Resolve(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("unnamed"))
+		require.True(t, container.HasDefault(SampleInterfaceType))
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "unnamed", instance.(SampleInterface).Name())
+	})
+	t.Run("slice order by name", func(t *testing.T) {
+		container := di.NewContainer(di.WithSliceOrderByName())
+		container.RegisterInstance(DependencyInterfaceType, NewSample("unnamed"))
+		for _, name := range []string{"charlie", "alpha", "bravo"} {
+			container.RegisterInstance(DependencyInterfaceType, NewSample(name), di.WithName(name))
+		}
+		require.NoError(t, container.RegisterConstructor(NewAggregate))
+
+		instance, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"alpha", "bravo", "charlie", "unnamed"}, instance.(AggregateInterface).Names())
+	})
+	t.Run("map key from metadata", func(t *testing.T) {
+		container := di.NewContainer(di.WithMapKeyFromMetadata("route"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("users"), di.WithName("one"), di.WithMetadata("route", "/users"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("orders"), di.WithMetadata("route", "/orders"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("health"), di.WithName("health"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("ignored"))
+
+		m, err := container.ResolveMap(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(m))
+		require.Equal(t, "users", m["/users"].(DependencyInterface).Name())
+		require.Equal(t, "orders", m["/orders"].(DependencyInterface).Name())
+		require.Equal(t, "health", m["health"].(DependencyInterface).Name())
+
+		result, err := di.Invoke(container, func(handlers map[string]DependencyInterface) string {
+			return handlers["/orders"].Name()
+		})
+		require.NoError(t, err)
+		require.Equal(t, "orders", result)
+	})
+	t.Run("map key from metadata must be string", func(t *testing.T) {
+		container := di.NewContainer(di.WithMapKeyFromMetadata("route"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("users"), di.WithMetadata("route", 1))
+		_, err := container.ResolveMap(DependencyInterfaceType)
+		require.Error(t, err)
+	})
+	t.Run("lifetime", func(t *testing.T) {
+		type test struct {
+			name      string
+			container di.Container
+		}
+		tests := []test{
+			{"can register default lifetime",
+				func() di.Container {
+					container := di.NewContainer(di.WithDefaultLifetime(di.LifetimeStatic))
+					err := container.RegisterConstructor(NewStorage)
+					require.NoError(t, err)
+					return container
+				}()},
+			{"can register lifetime",
+				func() di.Container {
+					container := di.NewContainer()
+					err := container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic))
+					require.NoError(t, err)
+					return container
+				}()},
+			{"can register default lifetime func",
+				func() di.Container {
+					container := di.NewContainer(di.WithDefaultLifetimeFunc(func(t reflect.Type) di.Lifetime {
+						if t == StorageType {
+							return di.LifetimeStatic
+						}
+						return di.LifetimePerRequest
+					}))
+					err := container.RegisterConstructor(NewStorage)
+					require.NoError(t, err)
+					return container
+				}()},
+			{"can override default lifetime", func() di.Container {
+				container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+				err := container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic))
+				require.NoError(t, err)
+				return container
+			}()},
+		}
+		for _, test := range tests {
+			obj, err := test.container.Resolve(StorageType)
+			require.NoError(t, err)
+
+			storage, ok := obj.(Storage)
+			require.True(t, ok)
+			storage.Set(1, "test")
+
+			obj, err = test.container.Resolve(StorageType)
+			require.NoError(t, err)
 
 			storage, ok = obj.(Storage)
 			require.True(t, ok)
@@ -369,6 +1419,279 @@ func TestConstructor(t *testing.T) {
 			require.Equal(t, "test", value)
 		}
 	})
+	t.Run("static constructs once concurrently", func(t *testing.T) {
+		container := di.NewContainer()
+		var count int32
+		container.RegisterDynamic(StorageType, func(r di.Resolver) (any, error) {
+			atomic.AddInt32(&count, 1)
+			return NewStorage(), nil
+		}, di.WithLifetime(di.LifetimeStatic))
+
+		const routines = 64
+		results := make([]any, routines)
+		errs := make([]error, routines)
+		var wg sync.WaitGroup
+		for i := 0; i < routines; i++ {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				results[index], errs[index] = container.Resolve(StorageType)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&count))
+		for _, result := range results {
+			require.Same(t, results[0], result)
+		}
+	})
+	t.Run("cache on success only", func(t *testing.T) {
+		container := di.NewContainer()
+		attempts := 0
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, fmt.Errorf("attempt %d failed", attempts)
+			}
+			return NewSample("success"), nil
+		}, di.WithLifetime(di.LifetimeStatic), di.WithCacheOnSuccessOnly())
+
+		for i := 1; i < 3; i++ {
+			_, err := container.Resolve(SampleInterfaceType)
+			require.Error(t, err)
+			require.Equal(t, i, attempts)
+		}
+		first, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		second, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, first, second)
+		require.Equal(t, 3, attempts)
+		require.Equal(t, 1, len(container.ConstructedInstances()))
+	})
+	t.Run("require non zero", func(t *testing.T) {
+		type Config struct {
+			Host string
+			Port int
+		}
+		configType := reflect.TypeOf(Config{})
+
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() Config {
+			return Config{}
+		}, di.WithRequireNonZero()))
+		_, err := container.Resolve(configType)
+		require.ErrorIs(t, err, di.ErrZeroValue)
+
+		container = di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() Config {
+			return Config{Host: "localhost"}
+		}, di.WithRequireNonZero()))
+		instance, err := container.Resolve(configType)
+		require.NoError(t, err)
+		require.Equal(t, "localhost", instance.(Config).Host)
+
+		container = di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func() Config {
+			return Config{}
+		}))
+		_, err = container.Resolve(configType)
+		require.NoError(t, err)
+	})
+	t.Run("static caches failure by default", func(t *testing.T) {
+		container := di.NewContainer()
+		attempts := 0
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			attempts++
+			return nil, fmt.Errorf("attempt %d failed", attempts)
+		}, di.WithLifetime(di.LifetimeStatic))
+		for i := 0; i < 2; i++ {
+			_, err := container.Resolve(SampleInterfaceType)
+			require.Error(t, err)
+		}
+		require.Equal(t, 1, attempts)
+	})
+	t.Run("resolve where", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, version := range []int{1, 3, 2} {
+			container.RegisterInstance(SampleInterfaceType,
+				NewSample(fmt.Sprintf("v%d", version)),
+				di.WithMetadata("version", version))
+		}
+		instance, err := container.ResolveWhere(SampleInterfaceType, func(info di.RegistrationInfo) bool {
+			return info.Metadata["version"] == 3
+		})
+		require.NoError(t, err)
+		require.Equal(t, "v3", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve where no match", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("v1"), di.WithMetadata("version", 1))
+		_, err := container.ResolveWhere(SampleInterfaceType, func(info di.RegistrationInfo) bool {
+			return info.Metadata["version"] == 2
+		})
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("resolve or construct", func(t *testing.T) {
+		container := di.NewContainer()
+		fallback := func(r di.Resolver) (any, error) {
+			return NewSample("fallback"), nil
+		}
+		instance, err := container.ResolveOrConstruct(SampleInterfaceType, fallback)
+		require.NoError(t, err)
+		require.Equal(t, "fallback", instance.(SampleInterface).Name())
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("registered"))
+		instance, err = container.ResolveOrConstruct(SampleInterfaceType, fallback)
+		require.NoError(t, err)
+		require.Equal(t, "registered", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve or construct registered failure", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		_, err := container.ResolveOrConstruct(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("fallback"), nil
+		})
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("resolve first", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("v1"))
+
+		instance, err := container.ResolveFirst(SampleInterfaceType, DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "v1", instance.(DependencyInterface).Name())
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("v2"))
+		instance, err = container.ResolveFirst(SampleInterfaceType, DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "v2", instance.(SampleInterface).Name())
+	})
+	t.Run("resolve first registered fails", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("v1"))
+		require.NoError(t, container.RegisterConstructor(NewSample))
+
+		_, err := container.ResolveFirst(SampleInterfaceType, DependencyInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), "string")
+	})
+	t.Run("resolve first none registered", func(t *testing.T) {
+		container := di.NewContainer()
+		_, err := container.ResolveFirst(SampleInterfaceType, DependencyInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), SampleInterfaceType.String())
+		require.Contains(t, err.Error(), DependencyInterfaceType.String())
+	})
+	t.Run("panic on resolve error", func(t *testing.T) {
+		container := di.NewContainer(di.WithPanicOnResolveError())
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.Panics(t, func() {
+			_, _ = container.Resolve(SampleInterfaceType)
+		})
+		require.Panics(t, func() {
+			_, _ = di.Invoke(container, func(s SampleInterface) {})
+		})
+		require.Panics(t, func() {
+			_, _ = container.ResolveWhere(SampleInterfaceType, func(di.RegistrationInfo) bool { return true })
+		})
+		require.Panics(t, func() {
+			_, _ = container.ResolveFirst(DependencyInterfaceType, SampleInterfaceType)
+		})
+		require.Panics(t, func() {
+			_, _, _ = container.ResolveCached(SampleInterfaceType)
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.Panics(t, func() {
+			_, _ = container.ResolveAllContext(ctx, SampleInterfaceType)
+		})
+	})
+	t.Run("panic on resolve error success", func(t *testing.T) {
+		container := di.NewContainer(di.WithPanicOnResolveError())
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NotPanics(t, func() {
+			_, err := container.Resolve(SampleInterfaceType)
+			require.NoError(t, err)
+		})
+	})
+	t.Run("no panic on resolve error by default", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NotPanics(t, func() {
+			_, err := container.Resolve(SampleInterfaceType)
+			require.ErrorIs(t, err, di.ErrNotExist)
+		})
+	})
+	t.Run("resolve cached", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic)))
+
+		first, cached, err := container.ResolveCached(StorageType)
+		require.NoError(t, err)
+		require.False(t, cached)
+
+		second, cached, err := container.ResolveCached(StorageType)
+		require.NoError(t, err)
+		require.True(t, cached)
+		require.Same(t, first, second)
+	})
+	t.Run("resolve cached per request", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimePerRequest)))
+
+		for i := 0; i < 2; i++ {
+			_, cached, err := container.ResolveCached(StorageType)
+			require.NoError(t, err)
+			require.False(t, cached)
+		}
+	})
+	t.Run("type defaults", func(t *testing.T) {
+		container := di.NewContainer(
+			di.WithDefaultLifetime(di.LifetimePerRequest),
+			di.WithTypeDefaults(SampleInterfaceType, di.WithLifetime(di.LifetimeStatic), di.WithMetadata("kind", "sample")))
+		container.RegisterInstance(SampleInterfaceType, NewSample("default"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("override"), di.WithName("override"), di.WithLifetime(di.LifetimeScoped))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("other"))
+
+		samples := container.Registrations(SampleInterfaceType)
+		require.Equal(t, di.LifetimeStatic, samples[0].Lifetime)
+		require.Equal(t, "sample", samples[0].Metadata["kind"])
+		require.Equal(t, di.LifetimeScoped, samples[1].Lifetime)
+		require.Equal(t, "sample", samples[1].Metadata["kind"])
+
+		other := container.Registrations(DependencyInterfaceType)
+		require.Equal(t, di.LifetimePerRequest, other[0].Lifetime)
+		require.Empty(t, other[0].Metadata)
+	})
+	t.Run("default lifetime func", func(t *testing.T) {
+		container := di.NewContainer(di.WithDefaultLifetimeFunc(func(t reflect.Type) di.Lifetime {
+			if t == StorageType {
+				return di.LifetimeStatic
+			}
+			return di.LifetimePerRequest
+		}))
+		require.NoError(t, container.RegisterConstructor(NewStorage))
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("test"), nil
+		})
+
+		storage := container.Registrations(StorageType)
+		require.Equal(t, di.LifetimeStatic, storage[0].Lifetime)
+
+		sample := container.Registrations(SampleInterfaceType)
+		require.Equal(t, di.LifetimePerRequest, sample[0].Lifetime)
+
+		first, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		second, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotSame(t, first, second)
+	})
 	t.Run("remove all", func(t *testing.T) {
 		names := []string{"one", "two", "three"}
 		container := di.NewContainer()
@@ -389,6 +1712,65 @@ func TestConstructor(t *testing.T) {
 		_, err = container.ResolveAll(SampleInterfaceType)
 		require.Error(t, err)
 	})
+	t.Run("append and remove", func(t *testing.T) {
+		container := di.NewContainer()
+		var handles []di.Handle
+		for _, name := range []string{"one", "two", "three"} {
+			handles = append(handles, container.AppendInstance(SampleInterfaceType, NewSample(name)))
+		}
+		require.NoError(t, container.Remove(handles[1]))
+
+		all, err := container.ResolveAll(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+		require.Equal(t, "one", all[0].(SampleInterface).Name())
+		require.Equal(t, "three", all[1].(SampleInterface).Name())
+
+		require.ErrorIs(t, container.Remove(handles[1]), di.ErrNotExist)
+	})
+	t.Run("remove named", func(t *testing.T) {
+		container := di.NewContainer()
+		handle := container.AppendInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		container.AppendInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
+		require.NoError(t, container.Remove(handle))
+
+		_, err := container.ResolveByName(SampleInterfaceType, "one")
+		require.ErrorIs(t, err, di.ErrNameNotExist)
+		_, err = container.ResolveByName(SampleInterfaceType, "two")
+		require.NoError(t, err)
+	})
+	t.Run("registrations", func(t *testing.T) {
+		container := di.NewContainer()
+		start := time.Now()
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
+		container.RegisterInstance(StringType, "test")
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("three"), di.WithName("three"))
+
+		infos := container.Registrations(SampleInterfaceType)
+		require.Equal(t, 3, len(infos))
+		for i := 1; i < len(infos); i++ {
+			require.Greater(t, infos[i].Index, infos[i-1].Index)
+		}
+		require.Equal(t, "three", infos[2].Name)
+		for _, info := range infos {
+			require.Equal(t, SampleInterfaceType, info.Type)
+			require.False(t, info.Time.Before(start))
+		}
+		require.Nil(t, container.Registrations(StorageType))
+	})
+	t.Run("count", func(t *testing.T) {
+		container := di.NewContainer()
+		require.Equal(t, 0, container.Count(SampleInterfaceType))
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("three"), di.WithName("three"))
+		require.Equal(t, 3, container.Count(SampleInterfaceType))
+
+		container.RemoveAll(SampleInterfaceType)
+		require.Equal(t, 0, container.Count(SampleInterfaceType))
+	})
 	t.Run("replace", func(t *testing.T) {
 		names := []string{"one", "two", "three"}
 		container := di.NewContainer()
@@ -410,3 +1792,30 @@ func TestConstructor(t *testing.T) {
 		require.Equal(t, 1, len(all))
 	})
 }
+
+func BenchmarkResolveInstance(b *testing.B) {
+	container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+	container.RegisterInstance(SampleInterfaceType, NewSample("test"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := container.Resolve(SampleInterfaceType)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResolveDynamic(b *testing.B) {
+	container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+	sample := NewSample("test")
+	container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+		return sample, nil
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := container.Resolve(SampleInterfaceType)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
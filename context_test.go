@@ -0,0 +1,67 @@
+package di_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey struct{}
+
+func TestResolveAllContext(t *testing.T) {
+	t.Run("passes context", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func(ctx context.Context) SampleInterface {
+			return NewSample(ctx.Value(contextKey{}).(string))
+		})
+		require.NoError(t, err)
+
+		ctx := context.WithValue(context.Background(), contextKey{}, "from context")
+		all, err := container.ResolveAllContext(ctx, SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(all))
+		require.Equal(t, "from context", all[0].(SampleInterface).Name())
+	})
+	t.Run("passes context to dependencies", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func(ctx context.Context) string {
+			return ctx.Value(contextKey{}).(string)
+		})
+		require.NoError(t, err)
+		require.NoError(t, container.RegisterConstructor(NewSample))
+
+		ctx := context.WithValue(context.Background(), contextKey{}, "nested")
+		all, err := container.ResolveAllContext(ctx, SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "nested", all[0].(SampleInterface).Name())
+	})
+	t.Run("validates", func(t *testing.T) {
+		container := di.NewContainer()
+		err := container.RegisterConstructor(func(ctx context.Context) SampleInterface {
+			return NewSample("validated")
+		})
+		require.NoError(t, err)
+		require.NoError(t, container.Validate())
+	})
+	t.Run("stops when canceled", func(t *testing.T) {
+		container := di.NewContainer()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var resolved []string
+		for _, name := range []string{"one", "two", "three"} {
+			name := name
+			container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+				resolved = append(resolved, name)
+				cancel()
+				return NewSample(name), nil
+			})
+		}
+
+		_, err := container.ResolveAllContext(ctx, SampleInterfaceType)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []string{"one"}, resolved)
+	})
+}
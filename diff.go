@@ -0,0 +1,55 @@
+package di
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Diff returns a description of each registration that was added to or removed from b compared to a,
+// and of each registration whose lifetime changed. Registrations are matched by type and name or key,
+// unnamed registrations of a type are matched by position.
+func Diff(a, b Container) []string {
+	before := registrationsByID(a)
+	after := registrationsByID(b)
+
+	var differences []string
+	for id, info := range before {
+		other, ok := after[id]
+		if !ok {
+			differences = append(differences, fmt.Sprintf("removed %s", id))
+			continue
+		}
+		if info.Lifetime != other.Lifetime {
+			differences = append(differences, fmt.Sprintf("changed %s lifetime from %s to %s", id, info.Lifetime, other.Lifetime))
+		}
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			differences = append(differences, fmt.Sprintf("added %s", id))
+		}
+	}
+	sort.Strings(differences)
+	return differences
+}
+
+// registrationsByID returns every registration of the container by a readable identifier
+func registrationsByID(c Container) map[string]RegistrationInfo {
+	registrations := map[string]RegistrationInfo{}
+	for _, t := range c.Types() {
+		unnamed := 0
+		for _, info := range c.Registrations(t) {
+			var id string
+			switch {
+			case info.Key != nil:
+				id = fmt.Sprintf("'%s' with key '%v'", t, info.Key)
+			case info.Name != "":
+				id = fmt.Sprintf("'%s' named '%s'", t, info.Name)
+			default:
+				unnamed++
+				id = fmt.Sprintf("'%s' #%d", t, unnamed)
+			}
+			registrations[id] = info
+		}
+	}
+	return registrations
+}
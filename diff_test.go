@@ -0,0 +1,42 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	newBase := func() di.Container {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+		return container
+	}
+	t.Run("same", func(t *testing.T) {
+		require.Empty(t, di.Diff(newBase(), newBase()))
+	})
+	t.Run("module", func(t *testing.T) {
+		module := func(c di.Container) {
+			c.RegisterInstance(SampleInterfaceType, NewSample("two"), di.WithName("two"))
+			c.RegisterInstance(StringType, "other")
+			c.RegisterInstance(DependencyInterfaceType, NewSample("key"), di.WithKey(1))
+		}
+		installed := newBase()
+		module(installed)
+		require.Equal(t, []string{
+			"added 'di_test.DependencyInterface' with key '1'",
+			"added 'di_test.SampleInterface' named 'two'",
+			"added 'string' #2",
+		}, di.Diff(newBase(), installed))
+	})
+	t.Run("removed and changed", func(t *testing.T) {
+		changed := di.NewContainer()
+		changed.RegisterInstance(StringType, "test", di.WithLifetime(di.LifetimePerRequest))
+		require.Equal(t, []string{
+			"changed 'string' #1 lifetime from static to per_request",
+			"removed 'di_test.SampleInterface' named 'one'",
+		}, di.Diff(newBase(), changed))
+	})
+}
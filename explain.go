@@ -0,0 +1,182 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Plan describes how a type would be resolved by the container
+type Plan struct {
+	// Type is the type being resolved
+	Type reflect.Type
+
+	// Name is the name of the chosen registration, empty if unnamed
+	Name string
+
+	// Lifetime is the lifetime of the chosen registration
+	Lifetime Lifetime
+
+	// Dependencies are the plans for each constructor parameter. Collection parameters
+	// contain one dependency per registration of the element type.
+	Dependencies []Plan
+}
+
+func (c *container) Explain(t reflect.Type) (Plan, error) {
+	return c.explain(t, map[*containerItem]struct{}{})
+}
+
+func (c *container) explain(t reflect.Type, visiting map[*containerItem]struct{}) (Plan, error) {
+	group, err := c.group(t)
+	if err != nil {
+		// a pointer can be resolved from a registration of the element type
+		if elementGroup, ok := c.pointerElementGroup(t); ok {
+			item, err := c.defaultItem(elementGroup, t.Elem())
+			if err != nil {
+				return Plan{}, err
+			}
+			return c.explainItem(t, item, visiting)
+		}
+		if c.assignable {
+			if item, ok := c.assignableItem(t); ok {
+				return c.explainItem(t, item, visiting)
+			}
+			if assignableErr := c.pointerReceiverError(t); assignableErr != nil {
+				return Plan{}, assignableErr
+			}
+		}
+
+		// autowired fields are injected when resolved so like a dynamic resolver they have no inspectable dependencies
+		if c.autowire && canAutowire(t) {
+			return Plan{Type: t, Lifetime: LifetimePerRequest}, nil
+		}
+		return Plan{}, err
+	}
+	item, err := c.defaultItem(group, t)
+	if err != nil {
+		return Plan{}, err
+	}
+	return c.explainItem(t, item, visiting)
+}
+
+func (c *container) explainItem(t reflect.Type, item *containerItem, visiting map[*containerItem]struct{}) (Plan, error) {
+	if _, ok := visiting[item]; ok {
+		return Plan{}, fmt.Errorf("%w: '%s'", ErrCircular, t.String())
+	}
+	visiting[item] = struct{}{}
+	defer delete(visiting, item)
+
+	plan := Plan{
+		Type:     t,
+		Name:     item.option.name,
+		Lifetime: item.option.lifetime,
+	}
+
+	// instances and dynamic resolvers have no inspectable dependencies
+	constructor := item.option.constructor
+	if constructor == nil {
+		return plan, nil
+	}
+
+	for i := 0; i < constructor.NumIn(); i++ {
+		parameterType := constructor.In(i)
+		var (
+			dependency Plan
+			err        error
+		)
+		switch {
+		case constructor.IsVariadic() && i == constructor.NumIn()-1:
+			dependency, err = c.explainAll(parameterType, visiting)
+
+			// a variadic parameter can be empty so a missing registration of its element type is not an error
+			if errors.Is(err, ErrNotExist) && !c.registered(parameterType.Elem()) {
+				dependency, err = Plan{Type: parameterType}, nil
+			}
+		case parameterType.Kind() == reflect.Array || parameterType.Kind() == reflect.Slice:
+			dependency, err = c.explainAll(parameterType, visiting)
+		case parameterType.Kind() == reflect.Map && parameterType.Key().Kind() == reflect.String:
+			dependency, err = c.explainMap(parameterType, visiting)
+		case parameterType.Implements(optionalParameterType):
+			optional := reflect.Zero(parameterType).Interface().(optionalParameter)
+			dependency, err = c.explain(optional.optionalType(), visiting)
+
+			// an optional parameter can be absent so a missing registration of its type is not an error
+			if errors.Is(err, ErrNotExist) && !c.registered(optional.optionalType()) {
+				dependency, err = Plan{Type: parameterType}, nil
+			}
+		case parameterType == contextType && !c.registered(contextType):
+			// the context is supplied by the call that resolves the constructor, see ResolveAllContext
+			dependency = Plan{Type: parameterType}
+		default:
+			dependency, err = c.explain(parameterType, visiting)
+		}
+
+		// a constructor registered WithOptionalCollections receives an empty collection of an unregistered element type
+		if item.option.optionalCollections && isCollectionParameter(constructor, i) &&
+			errors.Is(err, ErrNotExist) && !c.registered(parameterType.Elem()) {
+			dependency, err = Plan{Type: parameterType}, nil
+		}
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Dependencies = append(plan.Dependencies, dependency)
+	}
+	return plan, nil
+}
+
+func (c *container) explainAll(t reflect.Type, visiting map[*containerItem]struct{}) (Plan, error) {
+	group, err := c.group(t.Elem())
+	if err != nil {
+		return Plan{}, err
+	}
+	plan := Plan{
+		Type: t,
+	}
+	for _, item := range c.sliceOrder(group) {
+		dependency, err := c.explainItem(t.Elem(), item, visiting)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Dependencies = append(plan.Dependencies, dependency)
+	}
+	return plan, nil
+}
+
+func (c *container) explainMap(t reflect.Type, visiting map[*containerItem]struct{}) (Plan, error) {
+	group, err := c.group(t.Elem())
+	if err != nil {
+		return Plan{}, err
+	}
+	items, err := c.mapItems(group)
+	if err != nil {
+		return Plan{}, err
+	}
+	keys := []string{}
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	plan := Plan{
+		Type: t,
+	}
+	for _, key := range keys {
+		dependency, err := c.explainItem(t.Elem(), items[key], visiting)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Dependencies = append(plan.Dependencies, dependency)
+	}
+	return plan, nil
+}
+
+// names returns the sorted names of the named items in the group
+func (g *containerItemGroup) names() []string {
+	var names []string
+	for name := range g.namedItems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
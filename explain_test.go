@@ -0,0 +1,128 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type Explained interface {
+	Sample() SampleInterface
+}
+
+type explained struct {
+	sample  SampleInterface
+	storage Storage
+}
+
+func (e *explained) Sample() SampleInterface {
+	return e.sample
+}
+
+func NewExplained(sample SampleInterface, storage Storage) Explained {
+	return &explained{
+		sample:  sample,
+		storage: storage,
+	}
+}
+
+var ExplainedType = reflect.TypeOf((*Explained)(nil)).Elem()
+
+func TestExplain(t *testing.T) {
+	t.Run("constructor dependencies", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample, di.WithLifetime(di.LifetimePerRequest)))
+		require.NoError(t, container.RegisterConstructor(NewStorage, di.WithLifetime(di.LifetimeStatic)))
+		require.NoError(t, container.RegisterConstructor(NewExplained, di.WithName("explained")))
+
+		plan, err := container.Explain(ExplainedType)
+		require.NoError(t, err)
+		require.Equal(t, ExplainedType, plan.Type)
+		require.Equal(t, "explained", plan.Name)
+		require.Equal(t, 2, len(plan.Dependencies))
+
+		sample := plan.Dependencies[0]
+		require.Equal(t, SampleInterfaceType, sample.Type)
+		require.Equal(t, di.LifetimePerRequest, sample.Lifetime)
+		require.Equal(t, 1, len(sample.Dependencies))
+		require.Equal(t, StringType, sample.Dependencies[0].Type)
+
+		storage := plan.Dependencies[1]
+		require.Equal(t, StorageType, storage.Type)
+		require.Equal(t, di.LifetimeStatic, storage.Lifetime)
+		require.Equal(t, 0, len(storage.Dependencies))
+	})
+	t.Run("does not resolve", func(t *testing.T) {
+		container := di.NewContainer()
+		called := false
+		container.RegisterDynamic(StringType, func(r di.Resolver) (any, error) {
+			called = true
+			return "test", nil
+		})
+		require.NoError(t, container.RegisterConstructor(NewSample))
+
+		_, err := container.Explain(SampleInterfaceType)
+		require.NoError(t, err)
+		require.False(t, called)
+	})
+	t.Run("collection dependencies", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("one"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("two"))
+		require.NoError(t, container.RegisterConstructor(NewAggregate))
+
+		plan, err := container.Explain(AggregateInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(plan.Dependencies))
+		require.Equal(t, 2, len(plan.Dependencies[0].Dependencies))
+	})
+	t.Run("pointer from value", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(ChildType, Child{Something: "value"})
+		require.NoError(t, container.RegisterConstructor(func(child *Child) SampleInterface {
+			return NewSample(child.Something)
+		}))
+
+		plan, err := container.Explain(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(plan.Dependencies))
+		require.Equal(t, reflect.PointerTo(ChildType), plan.Dependencies[0].Type)
+		require.NoError(t, container.Validate())
+	})
+	t.Run("assignable", func(t *testing.T) {
+		container := di.NewContainer(di.WithAssignableResolution())
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), &SampleStruct{name: "test"})
+		require.NoError(t, container.RegisterConstructor(func(sample SampleInterface) DependencyInterface {
+			return sample
+		}))
+
+		plan, err := container.Explain(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(plan.Dependencies))
+		require.Equal(t, SampleInterfaceType, plan.Dependencies[0].Type)
+		require.NoError(t, container.Validate())
+	})
+	t.Run("autowire", func(t *testing.T) {
+		container := di.NewContainer(di.WithAutowire())
+		container.RegisterInstance(InjectedType, &injected{})
+		require.NoError(t, container.RegisterConstructor(func(wrapper *Wrapper) SampleInterface {
+			return NewSample("wrapped")
+		}))
+
+		plan, err := container.Explain(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(plan.Dependencies))
+		require.Equal(t, reflect.TypeOf(&Wrapper{}), plan.Dependencies[0].Type)
+		require.NoError(t, container.Validate())
+	})
+	t.Run("missing dependency", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewSample))
+
+		_, err := container.Explain(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
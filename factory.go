@@ -0,0 +1,42 @@
+//go:build go1.18
+
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterFactory registers a factory for T whose parameter P is supplied at resolve time with ResolveWith
+// instead of being resolved from the container. Factories default to a per request lifetime.
+func RegisterFactory[T any, P any](container Container, factory func(P) (T, error), options ...InstanceRegistrationOption) {
+	p := reflect.TypeOf((*P)(nil)).Elem()
+	options = append([]InstanceRegistrationOption{WithLifetime(LifetimePerRequest)}, options...)
+	RegisterDynamic(container, func(r Resolver) (T, error) {
+		var zero T
+		o, ok := r.(overrideResolver)
+		if !ok {
+			return zero, fmt.Errorf("%w: factory parameter '%s' must be supplied with ResolveWith", ErrNotExist, p)
+		}
+		instance, ok := o.override(p)
+		if !ok {
+			return zero, fmt.Errorf("%w: factory parameter '%s' must be supplied with ResolveWith", ErrNotExist, p)
+		}
+		parameter, err := cast[P](p, instance)
+		if err != nil {
+			return zero, err
+		}
+		return factory(parameter)
+	}, options...)
+}
+
+// ResolveWith resolves T from the container using the overrides before registrations
+func ResolveWith[T any](container Container, overrides ...any) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	instance, err := container.ResolveWith(t, overrides...)
+	if err != nil {
+		return zero, err
+	}
+	return cast[T](t, instance)
+}
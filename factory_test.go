@@ -0,0 +1,50 @@
+//go:build go1.18
+
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactory(t *testing.T) {
+	t.Run("resolve with parameter", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterFactory(container, func(name string) (SampleInterface, error) {
+			return NewSample(name), nil
+		})
+
+		first, err := di.ResolveWith[SampleInterface](container, "first")
+		require.NoError(t, err)
+		require.Equal(t, "first", first.Name())
+
+		second, err := di.ResolveWith[SampleInterface](container, "second")
+		require.NoError(t, err)
+		require.Equal(t, "second", second.Name())
+	})
+	t.Run("parameter not from container", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "registered")
+		di.RegisterFactory(container, func(name string) (SampleInterface, error) {
+			return NewSample(name), nil
+		})
+
+		_, err := di.Resolve[SampleInterface](container)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("overrides dependencies", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "registered")
+		require.NoError(t, container.RegisterConstructor(NewSample, di.WithLifetime(di.LifetimePerRequest)))
+
+		instance, err := di.ResolveWith[SampleInterface](container, "override")
+		require.NoError(t, err)
+		require.Equal(t, "override", instance.Name())
+
+		instance, err = di.Resolve[SampleInterface](container)
+		require.NoError(t, err)
+		require.Equal(t, "registered", instance.Name())
+	})
+}
@@ -3,6 +3,7 @@
 package di
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -20,6 +21,40 @@ func RegisterDynamic[T any](container Container, delegate func(Resolver) (T, err
 	}, options...)
 }
 
+// RegisterDynamicNamed registers a dynamic resolver for T with the given name
+func RegisterDynamicNamed[T any](container Container, name string, delegate func(Resolver) (T, error), options ...InstanceRegistrationOption) {
+	options = append([]InstanceRegistrationOption{WithName(name)}, options...)
+	RegisterDynamic(container, delegate, options...)
+}
+
+// RegisterDynamicKeyed registers a dynamic resolver for T with the given key
+func RegisterDynamicKeyed[T any, K comparable](container Container, key K, delegate func(Resolver) (T, error), options ...InstanceRegistrationOption) {
+	options = append([]InstanceRegistrationOption{WithKey(key)}, options...)
+	RegisterDynamic(container, delegate, options...)
+}
+
+// RegisterNamedInstances registers each instance in the map as T under its key as the name
+func RegisterNamedInstances[T any](container Container, instances map[string]T, options ...InstanceRegistrationOption) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	m := make(map[string]any, len(instances))
+	for name, instance := range instances {
+		m[name] = instance
+	}
+	container.RegisterNamedInstances(t, m, options...)
+}
+
+// RegisterError registers T with a resolver that always returns the given error
+func RegisterError[T any](container Container, err error, options ...InstanceRegistrationOption) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	container.RegisterError(t, err, options...)
+}
+
+// Count returns the number of registrations for T
+func Count[T any](container Container) int {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return container.Count(t)
+}
+
 func ReplaceDynamic[T any](container Container, delegate func(Resolver) (T, error), options ...InstanceRegistrationOption) {
 	t := reflect.TypeOf((*T)(nil)).Elem()
 	container.ReplaceDynamic(t, func(r Resolver) (any, error) {
@@ -27,11 +62,27 @@ func ReplaceDynamic[T any](container Container, delegate func(Resolver) (T, erro
 	}, options...)
 }
 
-// Resolve resolves the given type with the given resolver
+// ReplaceByName replaces the registration of T with the given name, leaving other registrations of T intact
+func ReplaceByName[T any](container Container, name string, instance T, options ...InstanceRegistrationOption) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	container.ReplaceByName(t, name, instance, options...)
+}
+
+// ReplaceDynamicByName replaces the registration of T with the given name with the delegate, leaving other registrations of T intact
+func ReplaceDynamicByName[T any](container Container, name string, delegate func(Resolver) (T, error), options ...InstanceRegistrationOption) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	container.ReplaceDynamicByName(t, name, func(r Resolver) (any, error) {
+		return delegate(r)
+	}, options...)
+}
+
+// Resolve resolves the given type with the given resolver.
+// If T is a slice or a map with string keys that is not registered, it is resolved like a constructor parameter
+// from all registrations or the named registrations of the element type.
 func Resolve[T any](resolver Resolver) (T, error) {
 	var zero T
 	t := reflect.TypeOf((*T)(nil)).Elem()
-	instance, err := resolver.Resolve(t)
+	instance, err := resolveType(resolver, t)
 	if err != nil {
 		return zero, err
 	}
@@ -42,6 +93,102 @@ func Resolve[T any](resolver Resolver) (T, error) {
 	return cast, nil
 }
 
+// ResolveOrConstruct resolves T or, if T is not registered, returns the instance created by the fallback
+func ResolveOrConstruct[T any](container Container, fallback func(Resolver) (T, error)) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	instance, err := container.ResolveOrConstruct(t, func(r Resolver) (any, error) {
+		return fallback(r)
+	})
+	if err != nil {
+		return zero, err
+	}
+	return cast[T](t, instance)
+}
+
+// resolveType resolves t, or if t is a collection type that is not registered, resolves it from its element type.
+// Without a way to check registrations the collection is resolved when resolving t fails with ErrNotExist.
+func resolveType(resolver Resolver, t reflect.Type) (any, error) {
+	checker, ok := resolver.(registrationChecker)
+	if ok && !checker.registered(t) && isCollectionType(t) {
+		return resolveCollection(resolver, t, nil)
+	}
+	instance, err := resolver.Resolve(t)
+	if !ok && errors.Is(err, ErrNotExist) {
+		return resolveCollection(resolver, t, err)
+	}
+	return instance, err
+}
+
+// isCollectionType returns true if t is a slice or a map with string keys
+func isCollectionType(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// resolveCollection resolves a slice type from all registrations of its element type and a map type with string keys
+// from the named registrations of its element type. Any other type returns the original error.
+func resolveCollection(resolver Resolver, t reflect.Type, err error) (any, error) {
+	switch {
+	case t.Kind() == reflect.Slice:
+		value, err := resolveSlice(resolver, t)
+		if err != nil {
+			return nil, err
+		}
+		return value.Interface(), nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		value, err := resolveMap(resolver, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return value.Convert(t).Interface(), nil
+	}
+	return nil, err
+}
+
+// ResolveAs resolves the registration for the Concrete type and returns it as the Iface type
+func ResolveAs[Iface any, Concrete any](resolver Resolver) (Iface, error) {
+	var zero Iface
+	instance, err := Resolve[Concrete](resolver)
+	if err != nil {
+		return zero, err
+	}
+	t := reflect.TypeOf((*Iface)(nil)).Elem()
+	return cast[Iface](t, instance)
+}
+
+// MustResolve resolves the given type with the given resolver and panics if resolution fails.
+// It is intended for non-recoverable setup like wiring in main or tests, not for library code.
+func MustResolve[T any](resolver Resolver) T {
+	instance, err := Resolve[T](resolver)
+	if err != nil {
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		panic(fmt.Sprintf("di: unable to resolve '%s': %s", t.String(), err))
+	}
+	return instance
+}
+
+// TryResolve resolves the given type with the given resolver and returns false if it can not be resolved
+func TryResolve[T any](resolver Resolver) (T, bool) {
+	instance, err := Resolve[T](resolver)
+	return instance, err == nil
+}
+
+// TryResolveByName resolves the given type with the resolver and name and returns false if it can not be resolved
+func TryResolveByName[T any](resolver Resolver, name string) (T, bool) {
+	instance, err := ResolveByName[T](resolver, name)
+	return instance, err == nil
+}
+
+// TryResolveAll resolves every registration of the given type and returns false with an empty slice
+// if nothing is registered or any registration can not be resolved
+func TryResolveAll[T any](resolver Resolver) ([]T, bool) {
+	instances, err := ResolveAll[T](resolver)
+	if err != nil || len(instances) == 0 {
+		return []T{}, false
+	}
+	return instances, true
+}
+
 // ResolveByName resolves the given type with the resolver and name
 func ResolveByName[T any](resolver Resolver, name string) (T, error) {
 	var zero T
@@ -57,6 +204,40 @@ func ResolveByName[T any](resolver Resolver, name string) (T, error) {
 	return cast, nil
 }
 
+// ResolveByKey resolves the given type with the resolver and key
+func ResolveByKey[T any](resolver Resolver, key any) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	instance, err := resolver.ResolveByKey(t, key)
+	if err != nil {
+		return zero, err
+	}
+	return cast[T](t, instance)
+}
+
+// ResolveKeyedMap resolves the registrations of V that were registered with a key of type K as a map by their key.
+// Registrations with keys of other types are skipped.
+func ResolveKeyedMap[K comparable, V any](resolver Resolver) (map[K]V, error) {
+	t := reflect.TypeOf((*V)(nil)).Elem()
+	instances, err := resolver.ResolveKeyed(t)
+	if err != nil {
+		return nil, err
+	}
+	casts := map[K]V{}
+	for key, instance := range instances {
+		k, ok := key.(K)
+		if !ok {
+			continue
+		}
+		cast, err := cast[V](t, instance)
+		if err != nil {
+			return nil, err
+		}
+		casts[k] = cast
+	}
+	return casts, nil
+}
+
 func ResolveAll[T any](resolver Resolver) ([]T, error) {
 	t := reflect.TypeOf((*T)(nil)).Elem()
 	instances, err := resolver.ResolveAll(t)
@@ -75,6 +256,46 @@ func ResolveAll[T any](resolver Resolver) ([]T, error) {
 	return casts, nil
 }
 
+type groupOptions struct {
+	skipUncastable bool
+}
+
+// GroupOption changes how ResolveGroup converts group members
+type GroupOption func(*groupOptions)
+
+// WithSkipUncastable skips group members that can not be cast to the requested type instead of failing
+func WithSkipUncastable() GroupOption {
+	return func(o *groupOptions) {
+		o.skipUncastable = true
+	}
+}
+
+// ResolveGroup resolves the members of the named group as T
+func ResolveGroup[T any](container Container, group string, options ...GroupOption) ([]T, error) {
+	o := &groupOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	instances, err := container.ResolveGroup(group)
+	if err != nil {
+		return nil, err
+	}
+
+	casts := []T{}
+	for _, instance := range instances {
+		cast, err := cast[T](t, instance)
+		if err != nil {
+			if o.skipUncastable {
+				continue
+			}
+			return nil, err
+		}
+		casts = append(casts, cast)
+	}
+	return casts, nil
+}
+
 func cast[T any](t reflect.Type, instance any) (T, error) {
 	var zero T
 	cast, ok := instance.(T)
@@ -83,3 +304,49 @@ func cast[T any](t reflect.Type, instance any) (T, error) {
 	}
 	return cast, nil
 }
+
+// InvokeWith invokes the function like Invoke, using the overrides for any parameter or dependency they can be assigned to
+// before falling back to registrations, and returns the first result as T
+func InvokeWith[T any](resolver Resolver, fn any, overrides ...any) (T, error) {
+	var zero T
+	r, ok := resolver.(overridesResolver)
+	if !ok && len(overrides) > 0 {
+		return zero, fmt.Errorf("resolver of type '%T' does not support overrides", resolver)
+	}
+	if ok {
+		resolver = r.withOverrides(overrides)
+	}
+	result, err := Invoke(resolver, fn)
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, nil
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return cast[T](t, result)
+}
+
+// ResolveAndInject resolves *T and injects the inject tagged fields that were not set when it was constructed
+func ResolveAndInject[T any](resolver Resolver, options ...InjectOption) (*T, error) {
+	instance, err := Resolve[*T](resolver)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, nil
+	}
+	options = append(options, InjectOnlyZero())
+	err = Inject(resolver, instance, options...)
+	if err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// RegisterInjected registers a resolver that allocates a T and fills its inject tagged fields.
+// If T is a pointer type, the element is allocated and the pointer is returned
+func RegisterInjected[T any](container Container, options ...InstanceRegistrationOption) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return container.RegisterStruct(t, options...)
+}
@@ -3,6 +3,7 @@
 package di_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -25,6 +26,12 @@ func (r *runner) Run() {
 
 var RunnerType = reflect.TypeOf((*Runner)(nil)).Elem()
 
+// Partial has one field set by its constructor and one field filled by injection
+type Partial struct {
+	Constructed SampleInterface     `inject:""`
+	Injected    DependencyInterface `inject:""`
+}
+
 func TestGeneric(t *testing.T) {
 	t.Run("can register instance", func(t *testing.T) {
 		container := di.NewContainer()
@@ -99,4 +106,349 @@ func TestGeneric(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, instance)
 	})
+	t.Run("can register injected", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(InjectedType, &injected{})
+		require.NoError(t, di.RegisterInjected[Wrapper](container))
+
+		instance, err := di.Resolve[Wrapper](container)
+		require.NoError(t, err)
+		require.NotNil(t, instance.Injected)
+		require.Nil(t, instance.NotInjected)
+	})
+	t.Run("can register injected pointer", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(InjectedType, &injected{})
+		require.NoError(t, di.RegisterInjected[*Wrapper](container))
+
+		instance, err := di.Resolve[*Wrapper](container)
+		require.NoError(t, err)
+		require.NotNil(t, instance)
+		require.NotNil(t, instance.Injected)
+	})
+	t.Run("register injected requires struct", func(t *testing.T) {
+		container := di.NewContainer()
+		require.Error(t, di.RegisterInjected[Runner](container))
+	})
+	t.Run("register injected returns error", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, di.RegisterInjected[Wrapper](container))
+
+		_, err := di.Resolve[Wrapper](container)
+		require.Error(t, err)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("can register error", func(t *testing.T) {
+		container := di.NewContainer()
+		expected := errors.New("expected")
+		di.RegisterError[Runner](container, expected)
+		_, err := di.Resolve[Runner](container)
+		require.True(t, errors.Is(err, expected))
+	})
+	t.Run("must resolve", func(t *testing.T) {
+		container := di.NewContainer()
+		runner := NewRunner()
+		di.RegisterInstance(container, runner)
+		instance := di.MustResolve[Runner](container)
+		require.Equal(t, runner, instance)
+	})
+	t.Run("must resolve panics", func(t *testing.T) {
+		container := di.NewContainer()
+		require.PanicsWithValue(t,
+			"di: unable to resolve 'di_test.Runner': item does not exist in the container: 'di_test.Runner'",
+			func() {
+				di.MustResolve[Runner](container)
+			})
+	})
+	t.Run("can resolve as", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, &SampleStruct{name: "test"})
+		instance, err := di.ResolveAs[SampleInterface, *SampleStruct](container)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.Name())
+	})
+	t.Run("resolve as fails cast", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, &SampleStruct{name: "test"})
+		_, err := di.ResolveAs[Runner, *SampleStruct](container)
+		require.Error(t, err)
+	})
+	t.Run("can count", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, NewRunner())
+		di.RegisterInstance(container, NewRunner(), di.WithName("named"))
+		require.Equal(t, 2, di.Count[Runner](container))
+	})
+	t.Run("can register dynamic named", func(t *testing.T) {
+		container := di.NewContainer()
+		first := &runner{}
+		second := &runner{}
+		di.RegisterDynamicNamed(container, "first", func(r di.Resolver) (Runner, error) {
+			return first, nil
+		})
+		di.RegisterDynamicNamed(container, "second", func(r di.Resolver) (Runner, error) {
+			return second, nil
+		})
+
+		instance, err := di.ResolveByName[Runner](container, "first")
+		require.NoError(t, err)
+		require.Same(t, first, instance)
+
+		instance, err = di.ResolveByName[Runner](container, "second")
+		require.NoError(t, err)
+		require.Same(t, second, instance)
+	})
+	t.Run("can register dynamic keyed", func(t *testing.T) {
+		type RunnerKind int
+		const (
+			Fast RunnerKind = iota
+			Slow
+		)
+		container := di.NewContainer()
+		fast := &runner{}
+		slow := &runner{}
+		di.RegisterDynamicKeyed(container, Fast, func(r di.Resolver) (Runner, error) {
+			return fast, nil
+		})
+		di.RegisterDynamicKeyed(container, Slow, func(r di.Resolver) (Runner, error) {
+			return slow, nil
+		})
+
+		instance, err := di.ResolveByKey[Runner](container, Slow)
+		require.NoError(t, err)
+		require.Same(t, slow, instance)
+
+		_, err = di.ResolveByKey[Runner](container, 1)
+		require.ErrorIs(t, err, di.ErrKeyNotExist)
+
+		all, err := di.ResolveAll[Runner](container)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+	})
+	t.Run("can resolve keyed map", func(t *testing.T) {
+		type EventKind int
+		const (
+			Created EventKind = iota
+			Deleted
+		)
+		container := di.NewContainer()
+		var handled []EventKind
+		for _, kind := range []EventKind{Created, Deleted} {
+			kind := kind
+			di.RegisterDynamicKeyed(container, kind, func(r di.Resolver) (EventHandler, error) {
+				return func(e Event) error {
+					handled = append(handled, kind)
+					return nil
+				}, nil
+			})
+		}
+		di.RegisterDynamicKeyed(container, "other", func(r di.Resolver) (EventHandler, error) {
+			return func(e Event) error { return nil }, nil
+		})
+
+		handlers, err := di.ResolveKeyedMap[EventKind, EventHandler](container)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(handlers))
+		require.NoError(t, handlers[Deleted](Event{}))
+		require.NoError(t, handlers[Created](Event{}))
+		require.Equal(t, []EventKind{Deleted, Created}, handled)
+
+		_, err = di.ResolveKeyedMap[EventKind, SampleInterface](container)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("can replace by name", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two"} {
+			di.RegisterInstance(container, NewSample(name), di.WithName(name))
+		}
+		di.ReplaceByName(container, "one", NewSample("three"))
+
+		instance, err := di.ResolveByName[SampleInterface](container, "one")
+		require.NoError(t, err)
+		require.Equal(t, "three", instance.Name())
+
+		instance, err = di.ResolveByName[SampleInterface](container, "two")
+		require.NoError(t, err)
+		require.Equal(t, "two", instance.Name())
+		require.Equal(t, 2, di.Count[SampleInterface](container))
+	})
+	t.Run("can replace dynamic by name", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two"} {
+			di.RegisterInstance(container, NewSample(name), di.WithName(name))
+		}
+		di.ReplaceDynamicByName(container, "two", func(r di.Resolver) (SampleInterface, error) {
+			return NewSample("three"), nil
+		})
+
+		instance, err := di.ResolveByName[SampleInterface](container, "two")
+		require.NoError(t, err)
+		require.Equal(t, "three", instance.Name())
+
+		instance, err = di.ResolveByName[SampleInterface](container, "one")
+		require.NoError(t, err)
+		require.Equal(t, "one", instance.Name())
+		require.Equal(t, 2, di.Count[SampleInterface](container))
+	})
+	t.Run("can register named instances", func(t *testing.T) {
+		container := di.NewContainer()
+		var handled []string
+		handler := func(name string) EventHandler {
+			return func(e Event) error {
+				handled = append(handled, name)
+				return nil
+			}
+		}
+		di.RegisterNamedInstances(container, map[string]EventHandler{
+			"one":   handler("one"),
+			"two":   handler("two"),
+			"three": handler("three"),
+		})
+		for _, name := range []string{"one", "two", "three"} {
+			instance, err := di.ResolveByName[EventHandler](container, name)
+			require.NoError(t, err)
+			require.NoError(t, instance(Event{}))
+		}
+		require.Equal(t, []string{"one", "two", "three"}, handled)
+
+		handlers, err := container.ResolveMap(EventHandlerType)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(handlers))
+	})
+	t.Run("invoke with", func(t *testing.T) {
+		type Command struct {
+			Target string
+		}
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("handler"))
+		handle := func(sample SampleInterface, command Command) (string, error) {
+			return sample.Name() + " " + command.Target, nil
+		}
+
+		result, err := di.InvokeWith[string](container, handle, Command{Target: "one"})
+		require.NoError(t, err)
+		require.Equal(t, "handler one", result)
+
+		result, err = di.InvokeWith[string](container.ReadOnly(), handle, NewSample("override"), Command{Target: "two"})
+		require.NoError(t, err)
+		require.Equal(t, "override two", result)
+
+		_, err = di.InvokeWith[string](container, handle)
+		require.ErrorIs(t, err, di.ErrNotExist)
+
+		_, err = di.InvokeWith[int](container, handle, Command{})
+		require.Error(t, err)
+	})
+	t.Run("try resolve", func(t *testing.T) {
+		container := di.NewContainer()
+		_, ok := di.TryResolve[SampleInterface](container)
+		require.False(t, ok)
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("test"))
+		instance, ok := di.TryResolve[SampleInterface](container)
+		require.True(t, ok)
+		require.Equal(t, "test", instance.Name())
+	})
+	t.Run("try resolve by name", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"), di.WithName("one"))
+
+		instance, ok := di.TryResolveByName[SampleInterface](container, "one")
+		require.True(t, ok)
+		require.Equal(t, "one", instance.Name())
+
+		instance, ok = di.TryResolveByName[SampleInterface](container, "two")
+		require.False(t, ok)
+		require.Nil(t, instance)
+	})
+	t.Run("try resolve all", func(t *testing.T) {
+		container := di.NewContainer()
+		instances, ok := di.TryResolveAll[SampleInterface](container)
+		require.False(t, ok)
+		require.Empty(t, instances)
+
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"))
+		instances, ok = di.TryResolveAll[SampleInterface](container)
+		require.True(t, ok)
+		require.Equal(t, 2, len(instances))
+	})
+	t.Run("resolve or construct", func(t *testing.T) {
+		container := di.NewContainer()
+		fallback := func(r di.Resolver) (SampleInterface, error) {
+			return NewSample("fallback"), nil
+		}
+		instance, err := di.ResolveOrConstruct(container, fallback)
+		require.NoError(t, err)
+		require.Equal(t, "fallback", instance.Name())
+
+		di.RegisterInstance(container, NewSample("registered"))
+		instance, err = di.ResolveOrConstruct(container, fallback)
+		require.NoError(t, err)
+		require.Equal(t, "registered", instance.Name())
+	})
+	t.Run("resolve and inject", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("container"))
+		container.RegisterInstance(DependencyInterfaceType, NewSample("injected"))
+		require.NoError(t, container.RegisterConstructor(func() *Partial {
+			return &Partial{
+				Constructed: NewSample("constructor"),
+			}
+		}))
+
+		partial, err := di.ResolveAndInject[Partial](container)
+		require.NoError(t, err)
+		require.Equal(t, "constructor", partial.Constructed.Name())
+		require.Equal(t, "injected", partial.Injected.Name())
+	})
+	t.Run("resolve slice", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, NewRunner())
+		di.RegisterInstance(container, NewRunner())
+		runners, err := di.Resolve[[]Runner](container)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(runners))
+
+		samples, err := di.Resolve[[]SampleInterface](container)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Nil(t, samples)
+	})
+	t.Run("resolve map", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two"} {
+			di.RegisterInstance(container, NewRunner(), di.WithName(name))
+		}
+		runners, err := di.Resolve[map[string]Runner](container)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(runners))
+		require.Contains(t, runners, "one")
+		require.Contains(t, runners, "two")
+	})
+	t.Run("resolve slice panic on resolve error", func(t *testing.T) {
+		container := di.NewContainer(di.WithPanicOnResolveError())
+		di.RegisterInstance(container, NewRunner())
+		require.NotPanics(t, func() {
+			runners, err := di.Resolve[[]Runner](container)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(runners))
+		})
+	})
+	t.Run("resolve registered slice missing dependency", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, NewRunner())
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) []Runner {
+			return nil
+		}))
+		_, err := di.Resolve[[]Runner](container)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.ErrorContains(t, err, SampleInterfaceType.String())
+	})
+	t.Run("resolve registered slice", func(t *testing.T) {
+		container := di.NewContainer()
+		di.RegisterInstance(container, []byte("registered"))
+		data, err := di.Resolve[[]byte](container)
+		require.NoError(t, err)
+		require.Equal(t, "registered", string(data))
+	})
 }
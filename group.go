@@ -0,0 +1,43 @@
+package di
+
+import (
+	"fmt"
+	"sort"
+)
+
+func (c *container) ResolveGroup(group string) ([]any, error) {
+	var members []*containerItem
+	for _, g := range c.visibleGroups() {
+		for _, item := range g.ordered() {
+			if item.inGroup(group) {
+				members = append(members, item)
+			}
+		}
+	}
+	if len(members) == 0 {
+		return nil, c.check(fmt.Errorf("%w: group '%s'", ErrNotExist, group))
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].option.index < members[j].option.index
+	})
+
+	var all []any
+	for _, item := range members {
+		data, err := item.resolve(c, nil)
+		if err != nil {
+			return nil, c.check(err)
+		}
+		all = append(all, data)
+	}
+	return all, nil
+}
+
+// inGroup returns true if the item was registered in the group
+func (i *containerItem) inGroup(group string) bool {
+	for _, g := range i.option.groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type fastRunner struct{}
+
+func (r *fastRunner) Run() {}
+
+type slowRunner struct{}
+
+func (r *slowRunner) Run() {}
+
+func TestGroup(t *testing.T) {
+	t.Run("resolve group", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&fastRunner{}), &fastRunner{}, di.WithGroup("runners"))
+		container.RegisterInstance(reflect.TypeOf(&slowRunner{}), &slowRunner{}, di.WithGroup("runners"))
+		container.RegisterInstance(RunnerType, NewRunner(), di.WithGroup("runners"))
+		container.RegisterInstance(RunnerType, NewRunner())
+
+		all, err := container.ResolveGroup("runners")
+		require.NoError(t, err)
+		require.Equal(t, 3, len(all))
+		require.IsType(t, &fastRunner{}, all[0])
+		require.IsType(t, &slowRunner{}, all[1])
+	})
+	t.Run("resolve group generic", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&fastRunner{}), &fastRunner{}, di.WithGroup("runners"))
+		container.RegisterInstance(reflect.TypeOf(&slowRunner{}), &slowRunner{}, di.WithGroup("runners"))
+
+		runners, err := di.ResolveGroup[Runner](container, "runners")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(runners))
+	})
+	t.Run("resolve group uncastable", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&fastRunner{}), &fastRunner{}, di.WithGroup("runners"))
+		container.RegisterInstance(StringType, "not a runner", di.WithGroup("runners"))
+
+		_, err := di.ResolveGroup[Runner](container, "runners")
+		require.Error(t, err)
+
+		runners, err := di.ResolveGroup[Runner](container, "runners", di.WithSkipUncastable())
+		require.NoError(t, err)
+		require.Equal(t, 1, len(runners))
+	})
+	t.Run("resolve group in scope", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&fastRunner{}), &fastRunner{}, di.WithGroup("runners"))
+		scope := container.Scope()
+		scope.RegisterInstance(reflect.TypeOf(&slowRunner{}), &slowRunner{}, di.WithGroup("runners"))
+
+		all, err := scope.ResolveGroup("runners")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(all))
+		require.IsType(t, &fastRunner{}, all[0])
+		require.IsType(t, &slowRunner{}, all[1])
+	})
+	t.Run("resolve missing group", func(t *testing.T) {
+		container := di.NewContainer()
+		_, err := container.ResolveGroup("runners")
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
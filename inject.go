@@ -1,15 +1,53 @@
 package di
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type injectOptions struct {
+	useFieldName bool
+	onlyZero     bool
+}
+
+// InjectOption changes how Inject resolves fields
+type InjectOption func(*injectOptions)
+
+// InjectUseFieldName resolves an inject field without a tag value by using the field name as the registration name.
+// If there is no registration with that name, the field is resolved by type.
+func InjectUseFieldName() InjectOption {
+	return func(o *injectOptions) {
+		o.useFieldName = true
+	}
+}
+
+// InjectOnlyZero skips inject fields that already have a non zero value
+func InjectOnlyZero() InjectOption {
+	return func(o *injectOptions) {
+		o.onlyZero = true
+	}
+}
+
+// Inject resolves the inject tagged fields of the struct that instance points to. A field with a name in its tag,
+// for example `inject:"logger"`, receives the registration with that name. A Resolver or Container field without a
+// name in its tag receives the resolver in use, or the container the resolution was started from, without being
+// registered. A resolution not started from one of the Resolver methods of a container gives Container fields a
+// read only view of the container.
+func Inject(resolver Resolver, instance any, options ...InjectOption) error {
+	o := &injectOptions{}
+	for _, option := range options {
+		option(o)
+	}
 
-func Inject(resolver Resolver, instance any) error {
 	t := reflect.TypeOf(instance).Elem()
 	v := reflect.ValueOf(instance).Elem()
 
 	count := t.NumField()
 	for i := 0; i < count; i++ {
 		field := t.Field(i)
-		_, ok := field.Tag.Lookup("inject")
+		tag, ok := field.Tag.Lookup("inject")
 		if !ok {
 			continue
 		}
@@ -17,7 +55,10 @@ func Inject(resolver Resolver, instance any) error {
 		if !fieldValue.IsValid() || !fieldValue.CanAddr() || !fieldValue.CanSet() {
 			continue
 		}
-		resolved, err := resolver.Resolve(field.Type)
+		if o.onlyZero && !fieldValue.IsZero() {
+			continue
+		}
+		resolved, err := resolveField(resolver, field, tag, o)
 		if err != nil {
 			return err
 		}
@@ -25,3 +66,188 @@ func Inject(resolver Resolver, instance any) error {
 	}
 	return nil
 }
+
+func resolveField(resolver Resolver, field reflect.StructField, tag string, o *injectOptions) (any, error) {
+	name, defaultValue, hasDefault := parseInjectTag(tag)
+	if name == "" {
+		if self, ok := selfValue(resolver, field.Type); ok {
+			return self, nil
+		}
+	}
+	resolved, err := resolveFieldValue(resolver, field, name, o)
+
+	// the default is only used when the field is not registered, not when a registration fails
+	if err == nil || !hasDefault || !fieldUnregistered(resolver, field, name, err) {
+		return resolved, err
+	}
+	return parseDefault(field.Type, defaultValue)
+}
+
+func resolveFieldValue(resolver Resolver, field reflect.StructField, name string, o *injectOptions) (any, error) {
+	if name != "" {
+		return resolver.ResolveByName(field.Type, name)
+	}
+	if o.useFieldName {
+		resolved, err := resolver.ResolveByName(field.Type, field.Name)
+		if err == nil || !unregisteredName(resolver, field.Type, field.Name, err) {
+			return resolved, err
+		}
+	}
+	return resolver.Resolve(field.Type)
+}
+
+// fieldUnregistered returns true if err is caused by the field having no registration of its type, or with its name
+// if the tag has one, rather than by the registration failing to resolve
+func fieldUnregistered(resolver Resolver, field reflect.StructField, name string, err error) bool {
+	if name != "" {
+		return unregisteredName(resolver, field.Type, name, err)
+	}
+	return unregistered(resolver, field.Type, err)
+}
+
+// AutoWire resolves every exported field of the struct that instance points to by its type, or by the name in its
+// inject tag, whether or not it has an inject tag. A field whose type, or name, is not registered is left as is unless
+// its inject tag has the required option, for example `inject:",required"`. Inject tag defaults are honored.
+// A registered field that fails to resolve is an error.
+func AutoWire(resolver Resolver, instance any, options ...InjectOption) error {
+	o := &injectOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	v := reflect.ValueOf(instance)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("autowire instance of type '%T' must be a pointer to a struct", instance)
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		if !field.IsExported() || !fieldValue.CanSet() {
+			continue
+		}
+		if o.onlyZero && !fieldValue.IsZero() {
+			continue
+		}
+		tag := field.Tag.Get("inject")
+		resolved, err := resolveField(resolver, field, tag, o)
+		name, _, _ := parseInjectTag(tag)
+		if err != nil && fieldUnregistered(resolver, field, name, err) {
+			if hasInjectOption(tag, "required") {
+				return fmt.Errorf("unable to autowire required field '%s': %w", field.Name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to autowire field '%s': %w", field.Name, err)
+		}
+		fieldValue.Set(reflect.ValueOf(resolved))
+	}
+	return nil
+}
+
+// hasInjectOption returns true if the inject tag has the option before any default value
+func hasInjectOption(tag string, option string) bool {
+	_, options, _ := strings.Cut(tag, ",")
+	for options != "" {
+		if strings.HasPrefix(options, "default=") {
+			return false
+		}
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	resolverType  = reflect.TypeOf((*Resolver)(nil)).Elem()
+	containerType = reflect.TypeOf((*Container)(nil)).Elem()
+)
+
+// selfValue returns the resolver in use for a Resolver field and the container the resolution was started from
+// for a Container field so they can be injected without being registered
+func selfValue(resolver Resolver, t reflect.Type) (any, bool) {
+	switch t {
+	case resolverType:
+		return resolver, true
+	case containerType:
+		if c, ok := resolver.(Container); ok {
+			return c, true
+		}
+		if r, ok := resolver.(*resolution); ok {
+			return r.originContainer(), true
+		}
+	}
+	return nil, false
+}
+
+// parseInjectTag splits an inject tag like "name,default=42" into the name and the default value.
+// Everything after default= is the default value so it can contain commas.
+func parseInjectTag(tag string) (string, string, bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	const prefix = "default="
+	if strings.HasPrefix(options, prefix) {
+		return name, strings.TrimPrefix(options, prefix), true
+	}
+	if _, value, ok := strings.Cut(options, ","+prefix); ok {
+		return name, value, true
+	}
+	return name, "", false
+}
+
+// parseDefault parses the default value of an inject tag into a value of the string, integer, bool or float type t
+func parseDefault(t reflect.Type, value string) (any, error) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for '%s': %w", t, err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for '%s': %w", t, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for '%s': %w", t, err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("invalid default for '%s': %w", t, err)
+		}
+		v.SetFloat(f)
+	default:
+		return nil, fmt.Errorf("inject default is not supported for '%s'", t)
+	}
+	return v.Interface(), nil
+}
+
+// newInjected allocates a value of the struct or struct pointer type t and injects its fields
+func newInjected(resolver Resolver, t reflect.Type) (any, error) {
+	structType := t
+	if t.Kind() == reflect.Pointer {
+		structType = t.Elem()
+	}
+	instance := reflect.New(structType)
+	err := Inject(resolver, instance.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() == reflect.Pointer {
+		return instance.Interface(), nil
+	}
+	return instance.Elem().Interface(), nil
+}
@@ -1,6 +1,8 @@
 package di_test
 
 import (
+	"io"
+	"log"
 	"reflect"
 	"testing"
 
@@ -27,6 +29,39 @@ type Child struct {
 	Something string
 }
 
+type Database interface {
+	Name() string
+}
+
+type Repository struct {
+	Primary Database `inject:""`
+}
+
+type Settings struct {
+	Port    int     `inject:",default=42"`
+	Host    string  `inject:",default=localhost,local"`
+	Debug   bool    `inject:",default=true"`
+	Ratio   float64 `inject:",default=0.5"`
+	Timeout uint8   `inject:",default=7"`
+}
+
+// Aggregate has no inject tags and is filled by AutoWire
+type Aggregate struct {
+	Sample   SampleInterface
+	Name     string
+	Database Database
+	Missing  Injected
+	internal string
+}
+
+// Handler receives the resolver and container in use and a named logger
+type Handler struct {
+	Resolver  di.Resolver  `inject:""`
+	Container di.Container `inject:""`
+	Logger    *log.Logger  `inject:"logger"`
+}
+
+var DatabaseType = reflect.TypeOf((*Database)(nil)).Elem()
 var InjectedType = reflect.TypeOf((*Injected)(nil)).Elem()
 var ChildType = reflect.TypeOf((*Child)(nil)).Elem()
 var ParentType = reflect.TypeOf((*Parent)(nil)).Elem()
@@ -56,4 +91,228 @@ func TestInject(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "something", parent.Child.Something)
 	})
+	t.Run("use field name", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DatabaseType, NewSample("default"))
+		container.RegisterInstance(DatabaseType, NewSample("primary"), di.WithName("Primary"))
+
+		repository := Repository{}
+		err := di.Inject(container, &repository, di.InjectUseFieldName())
+		require.NoError(t, err)
+		require.Equal(t, "primary", repository.Primary.Name())
+	})
+	t.Run("use field name falls back", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DatabaseType, NewSample("default"))
+
+		repository := Repository{}
+		err := di.Inject(container, &repository, di.InjectUseFieldName())
+		require.NoError(t, err)
+		require.Equal(t, "default", repository.Primary.Name())
+	})
+	t.Run("field name ignored by default", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DatabaseType, NewSample("default"))
+		container.RegisterInstance(DatabaseType, NewSample("primary"), di.WithName("Primary"))
+
+		repository := Repository{}
+		err := di.Inject(container, &repository)
+		require.NoError(t, err)
+		require.Equal(t, "default", repository.Primary.Name())
+	})
+	t.Run("default", func(t *testing.T) {
+		container := di.NewContainer()
+		settings := &Settings{}
+		require.NoError(t, di.Inject(container, settings))
+		require.Equal(t, 42, settings.Port)
+		require.Equal(t, "localhost,local", settings.Host)
+		require.Equal(t, true, settings.Debug)
+		require.Equal(t, 0.5, settings.Ratio)
+		require.Equal(t, uint8(7), settings.Timeout)
+	})
+	t.Run("default ignored when registered", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(0), 8080)
+		settings := &Settings{}
+		require.NoError(t, di.Inject(container, settings))
+		require.Equal(t, 8080, settings.Port)
+	})
+	t.Run("default ignored when registration fails", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) int {
+			return len(s.Name())
+		}))
+		err := di.Inject(container, &Settings{})
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.ErrorContains(t, err, SampleInterfaceType.String())
+	})
+	t.Run("invalid default", func(t *testing.T) {
+		type invalid struct {
+			Port int `inject:",default=port"`
+		}
+		require.Error(t, di.Inject(di.NewContainer(), &invalid{}))
+	})
+	t.Run("only zero", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DatabaseType, NewSample("injected"))
+		repository := &Repository{
+			Primary: NewSample("set"),
+		}
+		require.NoError(t, di.Inject(container, repository, di.InjectOnlyZero()))
+		require.Equal(t, "set", repository.Primary.Name())
+
+		require.NoError(t, di.Inject(container, repository))
+		require.Equal(t, "injected", repository.Primary.Name())
+	})
+	t.Run("auto wire", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("sample"))
+		container.RegisterInstance(StringType, "name")
+		container.RegisterInstance(DatabaseType, NewSample("database"))
+
+		aggregate := &Aggregate{}
+		require.NoError(t, di.AutoWire(container, aggregate))
+		require.Equal(t, "sample", aggregate.Sample.Name())
+		require.Equal(t, "name", aggregate.Name)
+		require.Equal(t, "database", aggregate.Database.Name())
+		require.Nil(t, aggregate.Missing)
+		require.Empty(t, aggregate.internal)
+	})
+	t.Run("auto wire named", func(t *testing.T) {
+		type Named struct {
+			Primary SampleInterface `inject:"primary"`
+			Missing SampleInterface `inject:"missing"`
+		}
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("default"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("primary"), di.WithName("primary"))
+
+		named := &Named{}
+		require.NoError(t, di.AutoWire(container, named))
+		require.Equal(t, "primary", named.Primary.Name())
+		require.Nil(t, named.Missing)
+	})
+	t.Run("auto wire registration fails", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(d DependencyInterface) SampleInterface {
+			return NewSample(d.Name())
+		}))
+		err := di.AutoWire(container, &Aggregate{})
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.ErrorContains(t, err, "'Sample'")
+	})
+	t.Run("auto wire required", func(t *testing.T) {
+		type Required struct {
+			Name    string   `inject:",required"`
+			Missing Injected `inject:",required"`
+		}
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "name")
+		err := di.AutoWire(container, &Required{})
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), "'Missing'")
+
+		require.Error(t, di.AutoWire(container, Required{}))
+	})
+	t.Run("resolver", func(t *testing.T) {
+		container := di.NewContainer()
+		logger := log.New(io.Discard, "", 0)
+		container.RegisterInstance(reflect.TypeOf(logger), log.New(io.Discard, "default", 0))
+		container.RegisterInstance(reflect.TypeOf(logger), logger, di.WithName("logger"))
+
+		handler := &Handler{}
+		require.NoError(t, di.Inject(container, handler))
+		require.Same(t, container, handler.Resolver)
+		require.Same(t, container, handler.Container)
+		require.Same(t, logger, handler.Logger)
+		require.Equal(t, 0, container.Count(reflect.TypeOf((*di.Resolver)(nil)).Elem()))
+
+		require.NoError(t, container.RegisterStruct(reflect.TypeOf(&Handler{})))
+		instance, err := container.Resolve(reflect.TypeOf(&Handler{}))
+		require.NoError(t, err)
+		resolved := instance.(*Handler)
+		require.NotNil(t, resolved.Resolver)
+		require.Same(t, container, resolved.Container)
+		require.Same(t, logger, resolved.Logger)
+
+		found, err := resolved.Resolver.ResolveByName(reflect.TypeOf(logger), "logger")
+		require.NoError(t, err)
+		require.Same(t, logger, found)
+	})
+	t.Run("read only container", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(&log.Logger{}), log.New(io.Discard, "", 0), di.WithName("logger"))
+		require.NoError(t, container.RegisterStruct(reflect.TypeOf(&Handler{})))
+		readOnly := container.ReadOnly()
+
+		instance, err := readOnly.Resolve(reflect.TypeOf(&Handler{}))
+		require.NoError(t, err)
+		handler := instance.(*Handler)
+		require.Same(t, readOnly, handler.Container)
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			handler.Container.RegisterInstance(SampleInterfaceType, NewSample("plugin"))
+		})
+
+		// resolutions that are not started from a Resolver method only hand out a read only view
+		instance, err = readOnly.ResolveWhere(reflect.TypeOf(&Handler{}), func(di.RegistrationInfo) bool { return true })
+		require.NoError(t, err)
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			instance.(*Handler).Container.RegisterInstance(SampleInterfaceType, NewSample("plugin"))
+		})
+	})
+	t.Run("named default", func(t *testing.T) {
+		type named struct {
+			Port int `inject:"port,default=42"`
+		}
+		container := di.NewContainer()
+		container.RegisterInstance(reflect.TypeOf(0), 8080)
+		instance := &named{}
+		require.NoError(t, di.Inject(container, instance))
+		require.Equal(t, 42, instance.Port)
+
+		container.RegisterInstance(reflect.TypeOf(0), 9090, di.WithName("port"))
+		require.NoError(t, di.Inject(container, instance))
+		require.Equal(t, 9090, instance.Port)
+	})
+	t.Run("register struct", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(InjectedType, &injected{})
+		err := container.RegisterStruct(reflect.TypeOf(&Wrapper{}), di.WithLifetime(di.LifetimeStatic))
+		require.NoError(t, err)
+
+		first, err := container.Resolve(reflect.TypeOf(&Wrapper{}))
+		require.NoError(t, err)
+		wrapper, ok := first.(*Wrapper)
+		require.True(t, ok)
+		require.NotNil(t, wrapper.Injected)
+
+		second, err := container.Resolve(reflect.TypeOf(&Wrapper{}))
+		require.NoError(t, err)
+		require.Same(t, first, second)
+	})
+	t.Run("register struct per request", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(InjectedType, &injected{})
+		err := container.RegisterStruct(reflect.TypeOf(&Wrapper{}), di.WithLifetime(di.LifetimePerRequest))
+		require.NoError(t, err)
+
+		first, err := container.Resolve(reflect.TypeOf(&Wrapper{}))
+		require.NoError(t, err)
+		second, err := container.Resolve(reflect.TypeOf(&Wrapper{}))
+		require.NoError(t, err)
+		require.NotSame(t, first, second)
+	})
+	t.Run("register struct value", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(ChildType, Child{Something: "something"})
+		require.NoError(t, container.RegisterStruct(ParentType))
+
+		parent, err := container.Resolve(ParentType)
+		require.NoError(t, err)
+		require.Equal(t, "something", parent.(Parent).Child.Something)
+	})
+	t.Run("register struct requires struct", func(t *testing.T) {
+		container := di.NewContainer()
+		require.Error(t, container.RegisterStruct(StringType))
+	})
 }
@@ -0,0 +1,7 @@
+// Package config has a Config type with the same name as the Config type in the second package
+// so tests can check that identically named types are registered separately.
+package config
+
+type Config struct {
+	Name string
+}
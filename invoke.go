@@ -1,20 +1,117 @@
 package di
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"reflect"
+	"sync"
 )
 
-func Invoke(resolver Resolver, delegate any) (any, error) {
+type invokeOptions struct {
+	aggregateParamErrors bool
+	sharedPerCallCache   bool
+	optionalCollections  bool
+}
+
+// InvokeOption changes how Invoke resolves parameters
+type InvokeOption func(*invokeOptions)
+
+// WithAggregateParamErrors resolves every parameter before failing and returns one error
+// listing each parameter that could not be resolved instead of the first error.
+// The errors are joined with the error joiner of the container.
+func WithAggregateParamErrors() InvokeOption {
+	return func(o *invokeOptions) {
+		o.aggregateParamErrors = true
+	}
+}
+
+// WithSharedPerCallCache constructs each per request registration at most once while resolving the parameters of the call,
+// so parameters that depend on the same per request registration share one instance
+func WithSharedPerCallCache() InvokeOption {
+	return func(o *invokeOptions) {
+		o.sharedPerCallCache = true
+	}
+}
+
+// withOptionalCollections resolves the collection parameters of unregistered element types as empty collections
+func withOptionalCollections() InvokeOption {
+	return func(o *invokeOptions) {
+		o.optionalCollections = true
+	}
+}
+
+// newInvokeOptions applies the options to the default invoke options
+func newInvokeOptions(options ...InvokeOption) *invokeOptions {
+	o := &invokeOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+func Invoke(resolver Resolver, delegate any, options ...InvokeOption) (any, error) {
+	o := newInvokeOptions(options...)
+	if r, ok := resolver.(callCacheResolver); ok && o.sharedPerCallCache {
+		resolver = r.withCallCache()
+	}
 	t := reflect.TypeOf(delegate)
 	err := validateDelegateType(resolver, t)
 	if err != nil {
 		return nil, err
 	}
-	parameters, err := resolveParameters(resolver, t)
+	if o.aggregateParamErrors {
+		parameters, err := resolveAllParameters(resolver, t, o)
+		if err != nil {
+			return nil, invokeError(resolver, t, err)
+		}
+		return call(delegate, parameters)
+	}
+	parameters, err := resolveParameters(resolver, t, o)
+	if err != nil {
+		return nil, invokeError(resolver, t, err)
+	}
+	return call(delegate, parameters)
+}
+
+// invokeError describes what was being invoked when a parameter of the function type t failed to resolve.
+// Constructors invoked by the container are described by the registration they construct, so an error from
+// a nested constructor lists each registration in the dependency chain.
+func invokeError(resolver Resolver, t reflect.Type, err error) error {
+	if r, ok := resolver.(*resolution); ok && r.item != nil {
+		return fmt.Errorf("unable to construct '%s': %w", r.item.option.key, err)
+	}
+	return fmt.Errorf("unable to invoke '%s': %w", t, err)
+}
+
+// InvokeOptional invokes the delegate like Invoke but passes the zero value for any parameter that can not be resolved.
+// Each unresolved parameter is logged.
+func InvokeOptional(resolver Resolver, delegate any) (any, error) {
+	t := reflect.TypeOf(delegate)
+	err := validateDelegateType(resolver, t)
 	if err != nil {
 		return nil, err
 	}
+	parameters := []reflect.Value{}
+	for i := 0; i < t.NumIn(); i++ {
+		values, err := resolveParameter(resolver, t, i)
+		if err == nil {
+			parameters = append(parameters, values...)
+			continue
+		}
+		log.Printf("di: unable to resolve parameter %d of type '%s', using zero value: %s", i, t.In(i), err)
+
+		// variadic parameters can be omitted
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			continue
+		}
+		parameters = append(parameters, reflect.Zero(t.In(i)))
+	}
+	return call(delegate, parameters)
+}
+
+func call(delegate any, parameters []reflect.Value) (any, error) {
 	constructorValue := reflect.ValueOf(delegate)
 	results := constructorValue.Call(parameters)
 	if len(results) == 0 {
@@ -27,59 +124,271 @@ func Invoke(resolver Resolver, delegate any) (any, error) {
 		instance = reflect.Zero(results[0].Type()).Interface()
 	}
 
+	var err error
 	if len(results) == 2 {
+		// an async constructor returns a channel instead of an error
 		if !results[1].IsZero() {
-			err = results[1].Interface().(error)
+			err, _ = results[1].Interface().(error)
 		}
 	}
 	return instance, err
 }
 
 func validateDelegateType(r Resolver, t reflect.Type) error {
-	if t.Kind() != reflect.Func {
-		return fmt.Errorf("function '%s' must be a method", t.Elem())
+	if t == nil || t.Kind() != reflect.Func {
+		return fmt.Errorf("delegate of type '%v' must be a function", t)
 	}
 	return nil
 }
 
-func resolveParameters(resolver Resolver, t reflect.Type) ([]reflect.Value, error) {
+func resolveParameters(resolver Resolver, t reflect.Type, o *invokeOptions) ([]reflect.Value, error) {
 	// build up the parameter list
 	inCount := t.NumIn()
 	values := []reflect.Value{}
 	for i := 0; i < inCount; i++ {
-		parameterType := t.In(i)
-		if parameterType.Kind() == reflect.Array || parameterType.Kind() == reflect.Slice {
+		parameterValues, err := o.resolveParameter(resolver, t, i)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve parameter %d of type '%s': %w", i, t.In(i), err)
+		}
+		values = append(values, parameterValues...)
+	}
+	return values, nil
+}
 
-			// is the function variadic and is this the last parameter?
-			if t.IsVariadic() && i == inCount-1 {
-				valueArray, err := resolver.ResolveAll(parameterType.Elem())
-				if err != nil {
-					return nil, err
-				}
-				for _, v := range valueArray {
-					values = append(values, reflect.ValueOf(v))
-				}
-			} else {
-				slice, err := resolveSlice(resolver, parameterType)
+// resolveAllParameters resolves every parameter of the function type t and joins the errors of the parameters that fail
+func resolveAllParameters(resolver Resolver, t reflect.Type, o *invokeOptions) ([]reflect.Value, error) {
+	values := []reflect.Value{}
+	var errs []error
+	for i := 0; i < t.NumIn(); i++ {
+		parameterValues, err := o.resolveParameter(resolver, t, i)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to resolve parameter %d of type '%s': %w", i, t.In(i), err))
+			continue
+		}
+		values = append(values, parameterValues...)
+	}
+	if len(errs) > 0 {
+		return nil, joinResolverErrors(resolver, errs)
+	}
+	return values, nil
+}
+
+// resolveParameter resolves parameter i of the function type t. If the options allow optional collections,
+// a collection parameter whose element type is not registered resolves to an empty collection.
+func (o *invokeOptions) resolveParameter(resolver Resolver, t reflect.Type, i int) ([]reflect.Value, error) {
+	values, err := resolveParameter(resolver, t, i)
+	if err == nil || !o.optionalCollections || !isCollectionParameter(t, i) || !errors.Is(err, ErrNotExist) {
+		return values, err
+	}
+	checker, ok := resolver.(registrationChecker)
+	if !ok || checker.registered(t.In(i).Elem()) {
+		return nil, err
+	}
+	parameterType := t.In(i)
+	switch {
+	case t.IsVariadic() && i == t.NumIn()-1:
+		return []reflect.Value{}, nil
+	case parameterType.Kind() == reflect.Map:
+		return []reflect.Value{reflect.MakeMap(parameterType)}, nil
+	case parameterType.Kind() == reflect.Slice:
+		return []reflect.Value{reflect.MakeSlice(parameterType, 0, 0)}, nil
+	}
+	return []reflect.Value{reflect.Zero(parameterType)}, nil
+}
+
+// registrationChecker reports if a type has any registration, or a registration with a name, without resolving it
+type registrationChecker interface {
+	registered(t reflect.Type) bool
+	registeredName(t reflect.Type, name string) bool
+}
+
+// unregistered returns true if err is caused by t having no registration rather than by a registration of t failing
+// to resolve one of its dependencies. Without a way to check registrations only the error is used.
+func unregistered(resolver Resolver, t reflect.Type, err error) bool {
+	if !errors.Is(err, ErrNotExist) {
+		return false
+	}
+	checker, ok := resolver.(registrationChecker)
+	return !ok || !checker.registered(t)
+}
+
+// unregisteredName returns true if err is caused by t having no registration with the name rather than by that
+// registration failing to resolve one of its dependencies. Without a way to check registrations only the error is used.
+func unregisteredName(resolver Resolver, t reflect.Type, name string, err error) bool {
+	if !errors.Is(err, ErrNotExist) && !errors.Is(err, ErrNameNotExist) {
+		return false
+	}
+	checker, ok := resolver.(registrationChecker)
+	return !ok || !checker.registeredName(t, name)
+}
+
+// resolveParameter resolves the values for parameter i of the function type t.
+// A variadic parameter may produce any number of values.
+func resolveParameter(resolver Resolver, t reflect.Type, i int) ([]reflect.Value, error) {
+	parameterType := t.In(i)
+	if parameterType.Kind() == reflect.Array || parameterType.Kind() == reflect.Slice {
+
+		// is the function variadic and is this the last parameter?
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			valueArray, err := resolveVariadic(resolver, t)
+
+			// a variadic parameter can be empty so a missing registration of its element type is not an error
+			if unregistered(resolver, parameterType.Elem(), err) {
+				return []reflect.Value{}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			values := []reflect.Value{}
+			for _, v := range valueArray {
+				value, err := assignableValue(v, parameterType.Elem())
 				if err != nil {
 					return nil, err
 				}
-				values = append(values, slice)
+				values = append(values, value)
 			}
-		} else if parameterType.Kind() == reflect.Map && parameterType.Key().Kind() == reflect.String {
-			mapValue, err := resolveMap(resolver, parameterType.Elem())
-			if err != nil {
-				return nil, err
+			return values, nil
+		}
+		slice, err := resolveSlice(resolver, parameterType)
+		if err != nil {
+			return nil, err
+		}
+		return []reflect.Value{slice}, nil
+	}
+	if parameterType.Kind() == reflect.Map && parameterType.Key().Kind() == reflect.String {
+		mapValue, err := resolveMap(resolver, parameterType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return []reflect.Value{mapValue}, nil
+	}
+	if parameterType.Implements(optionalParameterType) {
+		value, err := resolveOptional(resolver, parameterType)
+		if err != nil {
+			return nil, err
+		}
+		return []reflect.Value{value}, nil
+	}
+	if parameterType == contextType {
+		if r, ok := resolver.(contextResolver); ok {
+			if ctx, ok := r.context(); ok {
+				return []reflect.Value{reflect.ValueOf(&ctx).Elem()}, nil
 			}
-			values = append(values, mapValue)
+		}
+	}
+	instance, err := resolver.Resolve(parameterType)
+	if err != nil {
+		return nil, err
+	}
+	value, err := assignableValue(instance, parameterType)
+	if err != nil {
+		return nil, err
+	}
+	return []reflect.Value{value}, nil
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// optionalParameter is implemented by Optional so optional parameters can be detected by type
+type optionalParameter interface {
+	optionalType() reflect.Type
+	optionalOf(instance any) (any, error)
+}
+
+var optionalParameterType = reflect.TypeOf((*optionalParameter)(nil)).Elem()
+
+// resolveOptional resolves the optional parameter type t, returning the zero value if its type is not registered
+func resolveOptional(resolver Resolver, t reflect.Type) (reflect.Value, error) {
+	optional := reflect.Zero(t).Interface().(optionalParameter)
+	instance, err := resolver.Resolve(optional.optionalType())
+	if unregistered(resolver, optional.optionalType(), err) {
+		return reflect.Zero(t), nil
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value, err := optional.optionalOf(instance)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(value), nil
+}
+
+// restResolver resolves every registration of a type except the one Resolve would return
+type restResolver interface {
+	resolveRest(t reflect.Type) ([]any, error)
+}
+
+// resolveVariadic resolves the values of the variadic parameter of the function type t.
+// If an earlier parameter has the same type as the variadic element, that parameter receives the
+// default registration and the variadic parameter receives the remaining registrations.
+func resolveVariadic(resolver Resolver, t reflect.Type) ([]any, error) {
+	elementType := t.In(t.NumIn() - 1).Elem()
+	for i := 0; i < t.NumIn()-1; i++ {
+		if t.In(i) != elementType {
+			continue
+		}
+		rest, ok := resolver.(restResolver)
+		if !ok {
+			break
+		}
+		return rest.resolveRest(elementType)
+	}
+	return resolver.ResolveAll(elementType)
+}
+
+// isCollectionParameter returns true if parameter i of the function type t is resolved as a collection
+func isCollectionParameter(t reflect.Type, i int) bool {
+	parameterType := t.In(i)
+	switch parameterType.Kind() {
+	case reflect.Array, reflect.Slice:
+		return true
+	case reflect.Map:
+		return parameterType.Key().Kind() == reflect.String
+	}
+	return false
+}
+
+// collectionCapture holds the collection parameters of a constructor after they are first resolved
+type collectionCapture struct {
+	mutex  sync.Mutex
+	values map[int][]reflect.Value
+}
+
+// invoke calls the constructor with its collection parameters resolved only the first time it is invoked
+func (c *collectionCapture) invoke(resolver Resolver, constructor any, o *invokeOptions) (any, error) {
+	t := reflect.TypeOf(constructor)
+	parameters := []reflect.Value{}
+	for i := 0; i < t.NumIn(); i++ {
+		var values []reflect.Value
+		var err error
+		if isCollectionParameter(t, i) {
+			values, err = c.capture(resolver, t, i, o)
 		} else {
-			value, err := resolver.Resolve(parameterType)
-			if err != nil {
-				return nil, err
-			}
-			values = append(values, reflect.ValueOf(value))
+			values, err = o.resolveParameter(resolver, t, i)
+		}
+		if err != nil {
+			err = fmt.Errorf("unable to resolve parameter %d of type '%s': %w", i, t.In(i), err)
+			return nil, invokeError(resolver, t, err)
 		}
+		parameters = append(parameters, values...)
+	}
+	return call(constructor, parameters)
+}
+
+func (c *collectionCapture) capture(resolver Resolver, t reflect.Type, i int, o *invokeOptions) ([]reflect.Value, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	values, ok := c.values[i]
+	if ok {
+		return values, nil
 	}
+	values, err := o.resolveParameter(resolver, t, i)
+	if err != nil {
+		return nil, err
+	}
+	c.values[i] = values
 	return values, nil
 }
 
@@ -95,13 +404,29 @@ func resolveSlice(resolver Resolver, t reflect.Type) (reflect.Value, error) {
 
 	// set indexes of the slice
 	for i, value := range valueArray {
+		v, err := assignableValue(value, t.Elem())
+		if err != nil {
+			return zero, err
+		}
 		ptr := slice.Index(i)
-		ptr.Set(reflect.ValueOf(value))
+		ptr.Set(v)
 	}
 
 	return slice, nil
 }
 
+// assignableValue returns the reflect value of the instance, validating that it can be assigned to t
+func assignableValue(instance any, t reflect.Type) (reflect.Value, error) {
+	if instance == nil {
+		return reflect.Zero(t), nil
+	}
+	v := reflect.ValueOf(instance)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("unable to assign instance of type '%s' to element type '%s'", v.Type(), t)
+	}
+	return v, nil
+}
+
 func resolveMap(resolver Resolver, t reflect.Type) (reflect.Value, error) {
 	var zero reflect.Value
 	m, err := resolver.ResolveMap(t)
@@ -115,7 +440,11 @@ func resolveMap(resolver Resolver, t reflect.Type) (reflect.Value, error) {
 	mapValue := reflect.MakeMap(mapType)
 
 	for k, v := range m {
-		mapValue.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+		value, err := assignableValue(v, valueType)
+		if err != nil {
+			return zero, err
+		}
+		mapValue.SetMapIndex(reflect.ValueOf(k), value)
 	}
 	return mapValue, nil
 }
@@ -1,6 +1,8 @@
 package di_test
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/patrickhuber/go-di"
@@ -49,6 +51,29 @@ func TestInvoke(t *testing.T) {
 		require.NoError(t, err)
 	})
 	t.Run("can invoke array parameter", func(t *testing.T) {})
+	t.Run("slice of pointers", func(t *testing.T) {
+		container := di.NewContainer()
+		sampleType := reflect.TypeOf(&SampleStruct{})
+		container.RegisterInstance(sampleType, &SampleStruct{name: "one"})
+		container.RegisterInstance(sampleType, &SampleStruct{name: "two"})
+		result, err := di.Invoke(container, func(samples []*SampleStruct) int {
+			return len(samples)
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+	t.Run("slice of pointers with value registration", func(t *testing.T) {
+		container := di.NewContainer()
+		sampleType := reflect.TypeOf(&SampleStruct{})
+		container.RegisterInstance(sampleType, &SampleStruct{name: "one"})
+		container.RegisterInstance(sampleType, SampleStruct{name: "two"})
+		_, err := di.Invoke(container, func(samples []*SampleStruct) int {
+			return len(samples)
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "'di_test.SampleStruct'")
+		require.Contains(t, err.Error(), "'*di_test.SampleStruct'")
+	})
 	t.Run("can invoke variadic parameter", func(t *testing.T) {})
 	t.Run("can invoke map parameter", func(t *testing.T) {})
 	t.Run("can invoke with error and value in return", func(t *testing.T) {})
@@ -67,4 +92,122 @@ func TestInvoke(t *testing.T) {
 		})
 		require.NoError(t, err)
 	})
+	t.Run("optional", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "hello")
+		myFunction := func(greeting string, sample SampleInterface, dependencies ...DependencyInterface) string {
+			require.Nil(t, sample)
+			require.Equal(t, 0, len(dependencies))
+			return greeting
+		}
+		result, err := di.InvokeOptional(container, myFunction)
+		require.NoError(t, err)
+		require.Equal(t, "hello", result)
+	})
+	t.Run("optional nil registration", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, nil)
+		container.RegisterInstance(StringType, 42)
+		myFunction := func(sample SampleInterface, greeting string) string {
+			require.Nil(t, sample)
+			return greeting
+		}
+		result, err := di.InvokeOptional(container, myFunction)
+		require.NoError(t, err)
+		require.Equal(t, "", result)
+
+		_, err = di.Invoke(container, myFunction)
+		require.Error(t, err)
+	})
+	t.Run("optional fails without option", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "hello")
+		myFunction := func(greeting string, sample SampleInterface) string {
+			return greeting
+		}
+		_, err := di.Invoke(container, myFunction)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("aggregate parameter errors", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "hello")
+		myFunction := func(sample SampleInterface, greeting string, dependency DependencyInterface) string {
+			return greeting
+		}
+		_, err := di.Invoke(container, myFunction, di.WithAggregateParamErrors())
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), SampleInterfaceType.String())
+		require.Contains(t, err.Error(), DependencyInterfaceType.String())
+		require.NotContains(t, err.Error(), "parameter 1")
+	})
+	t.Run("error path", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) DependencyInterface {
+			return s
+		}))
+		myFunction := func(greeting string, dependency DependencyInterface) string {
+			return greeting
+		}
+		container.RegisterInstance(StringType, "hello")
+		_, err := di.Invoke(container, myFunction)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.Contains(t, err.Error(), "unable to invoke 'func(string, di_test.DependencyInterface) string'")
+		require.Contains(t, err.Error(), "parameter 1 of type 'di_test.DependencyInterface'")
+		require.Contains(t, err.Error(), "unable to construct 'di_test.DependencyInterface'")
+		require.Contains(t, err.Error(), "parameter 0 of type 'di_test.SampleInterface'")
+	})
+	t.Run("aggregate parameter errors joiner", func(t *testing.T) {
+		var joined []error
+		custom := errors.New("custom")
+		container := di.NewContainer(di.WithErrorJoiner(func(errs []error) error {
+			joined = errs
+			return custom
+		}))
+		_, err := di.Invoke(container, func(sample SampleInterface, dependency DependencyInterface) {}, di.WithAggregateParamErrors())
+		require.ErrorIs(t, err, custom)
+		require.Equal(t, 2, len(joined))
+	})
+	t.Run("aggregate parameter errors success", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "hello")
+		result, err := di.Invoke(container, func(greeting string) string {
+			return greeting
+		}, di.WithAggregateParamErrors())
+		require.NoError(t, err)
+		require.Equal(t, "hello", result)
+	})
+	t.Run("shared per call cache", func(t *testing.T) {
+		newContainer := func(constructed *int) di.Container {
+			container := di.NewContainer(di.WithDefaultLifetime(di.LifetimePerRequest))
+			container.RegisterDynamic(StringType, func(r di.Resolver) (any, error) {
+				*constructed++
+				return "shared", nil
+			})
+			require.NoError(t, container.RegisterConstructor(NewSample))
+			require.NoError(t, container.RegisterConstructor(func(s string) DependencyInterface {
+				return NewSample(s)
+			}))
+			return container
+		}
+		myFunction := func(sample SampleInterface, dependency DependencyInterface) string {
+			return sample.Name() + " " + dependency.Name()
+		}
+
+		constructed := 0
+		container := newContainer(&constructed)
+		result, err := di.Invoke(container, myFunction)
+		require.NoError(t, err)
+		require.Equal(t, "shared shared", result)
+		require.Equal(t, 2, constructed)
+
+		constructed = 0
+		result, err = di.Invoke(container, myFunction, di.WithSharedPerCallCache())
+		require.NoError(t, err)
+		require.Equal(t, "shared shared", result)
+		require.Equal(t, 1, constructed)
+
+		_, err = di.Invoke(container.ReadOnly(), myFunction, di.WithSharedPerCallCache())
+		require.NoError(t, err)
+		require.Equal(t, 2, constructed)
+	})
 }
@@ -0,0 +1,73 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// String returns the name of the lifetime
+func (l Lifetime) String() string {
+	switch l {
+	case LifetimeStatic:
+		return "static"
+	case LifetimePerRequest:
+		return "per_request"
+	case LifetimeScoped:
+		return "scoped"
+	}
+	return fmt.Sprintf("lifetime(%d)", int(l))
+}
+
+type containerJSON struct {
+	Registrations []registrationJSON `json:"registrations"`
+}
+
+type registrationJSON struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Lifetime string `json:"lifetime"`
+
+	// Dependencies are the parameter types of the constructor, empty for instances and dynamic resolvers
+	Dependencies []string `json:"dependencies"`
+}
+
+func (c *container) ToJSON() ([]byte, error) {
+	groups := []*containerItemGroup{}
+	for _, group := range c.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].t.String() < groups[j].t.String()
+	})
+
+	document := containerJSON{
+		Registrations: []registrationJSON{},
+	}
+	for _, group := range groups {
+		for _, item := range group.ordered() {
+			document.Registrations = append(document.Registrations, item.json())
+		}
+	}
+	return json.Marshal(document)
+}
+
+// json describes the item without resolving it
+func (i *containerItem) json() registrationJSON {
+	registration := registrationJSON{
+		Type:         i.option.key,
+		Name:         i.option.name,
+		Lifetime:     i.option.lifetime.String(),
+		Dependencies: []string{},
+	}
+	if i.option.itemKey != nil {
+		registration.Key = fmt.Sprint(i.option.itemKey)
+	}
+	if constructor := i.option.constructor; constructor != nil {
+		for p := 0; p < constructor.NumIn(); p++ {
+			registration.Dependencies = append(registration.Dependencies, constructor.In(p).String())
+		}
+	}
+	return registration
+}
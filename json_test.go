@@ -0,0 +1,45 @@
+package di_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSON(t *testing.T) {
+	container := di.NewContainer()
+	container.RegisterInstance(StringType, "test")
+	require.NoError(t, container.RegisterConstructor(NewSample, di.WithLifetime(di.LifetimePerRequest)))
+	require.NoError(t, container.RegisterConstructor(NewAggregate, di.WithName("aggregate")))
+
+	data, err := container.ToJSON()
+	require.NoError(t, err)
+
+	var document struct {
+		Registrations []struct {
+			Type         string   `json:"type"`
+			Name         string   `json:"name"`
+			Lifetime     string   `json:"lifetime"`
+			Dependencies []string `json:"dependencies"`
+		} `json:"registrations"`
+	}
+	require.NoError(t, json.Unmarshal(data, &document))
+	require.Equal(t, 3, len(document.Registrations))
+
+	aggregate := document.Registrations[0]
+	require.Equal(t, AggregateInterfaceType.String(), aggregate.Type)
+	require.Equal(t, "aggregate", aggregate.Name)
+	require.Equal(t, "static", aggregate.Lifetime)
+	require.Equal(t, []string{"[]" + DependencyInterfaceType.String()}, aggregate.Dependencies)
+
+	sample := document.Registrations[1]
+	require.Equal(t, SampleInterfaceType.String(), sample.Type)
+	require.Equal(t, "per_request", sample.Lifetime)
+	require.Equal(t, []string{StringType.String()}, sample.Dependencies)
+
+	instance := document.Registrations[2]
+	require.Equal(t, StringType.String(), instance.Type)
+	require.Equal(t, []string{}, instance.Dependencies)
+}
@@ -0,0 +1,87 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Manifest describes registrations by type name and constructor identifier so they can be loaded from configuration
+type Manifest struct {
+	Registrations []ManifestRegistration `json:"registrations"`
+}
+
+// ManifestRegistration registers the constructor with the identifier for the type with the name
+type ManifestRegistration struct {
+	// Type is the name of the type returned by the constructor, as returned by reflect.Type.String
+	Type string `json:"type"`
+
+	// Constructor is the identifier of the constructor in the registry
+	Constructor string `json:"constructor"`
+
+	Name string `json:"name,omitempty"`
+
+	// Lifetime is static, per_request or scoped. The default lifetime of the container is used if it is empty
+	Lifetime string `json:"lifetime,omitempty"`
+}
+
+// LoadManifest registers the constructor from the registry for each registration in the manifest.
+// Every registration is validated before any is registered, the errors of invalid registrations are joined with the
+// container's error joiner into the returned error.
+func LoadManifest(container Container, manifest Manifest, registry map[string]any) error {
+	var errs []error
+	options := make([][]InstanceRegistrationOption, len(manifest.Registrations))
+	for i, registration := range manifest.Registrations {
+		registrationOptions, err := registration.validate(registry)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to load registration %d of type '%s': %w", i, registration.Type, err))
+		}
+		options[i] = registrationOptions
+	}
+	if len(errs) > 0 {
+		return joinResolverErrors(container, errs)
+	}
+	for i, registration := range manifest.Registrations {
+		err := container.RegisterConstructor(registry[registration.Constructor], options[i]...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to load registration %d of type '%s': %w", i, registration.Type, err))
+		}
+	}
+	return joinResolverErrors(container, errs)
+}
+
+// validate checks the constructor is in the registry and returns the type, then returns the registration options
+func (r ManifestRegistration) validate(registry map[string]any) ([]InstanceRegistrationOption, error) {
+	constructor, ok := registry[r.Constructor]
+	if !ok {
+		return nil, fmt.Errorf("constructor '%s' is not in the registry", r.Constructor)
+	}
+	if err := ValidateConstructor(constructor); err != nil {
+		return nil, err
+	}
+	returnType := reflect.TypeOf(constructor).Out(0)
+	if returnType.String() != r.Type {
+		return nil, fmt.Errorf("constructor '%s' returns '%s'", r.Constructor, returnType.String())
+	}
+	var options []InstanceRegistrationOption
+	if r.Name != "" {
+		options = append(options, WithName(r.Name))
+	}
+	if r.Lifetime != "" {
+		lifetime, err := parseLifetime(r.Lifetime)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithLifetime(lifetime))
+	}
+	return options, nil
+}
+
+// parseLifetime returns the lifetime with the name returned by Lifetime.String
+func parseLifetime(name string) (Lifetime, error) {
+	for _, lifetime := range []Lifetime{LifetimeStatic, LifetimePerRequest, LifetimeScoped} {
+		if lifetime.String() == name {
+			return lifetime, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown lifetime '%s'", name)
+}
@@ -0,0 +1,68 @@
+package di_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	registry := map[string]any{
+		"name":   func() string { return "test" },
+		"sample": NewSample,
+	}
+	t.Run("registers", func(t *testing.T) {
+		data := []byte(`{"registrations":[
+			{"type":"string","constructor":"name"},
+			{"type":"di_test.SampleInterface","constructor":"sample","name":"sample","lifetime":"per_request"}
+		]}`)
+		var manifest di.Manifest
+		require.NoError(t, json.Unmarshal(data, &manifest))
+
+		container := di.NewContainer()
+		require.NoError(t, di.LoadManifest(container, manifest, registry))
+
+		instance, err := container.ResolveByName(SampleInterfaceType, "sample")
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+
+		registrations := container.Registrations(SampleInterfaceType)
+		require.Equal(t, 1, len(registrations))
+		require.Equal(t, di.LifetimePerRequest, registrations[0].Lifetime)
+	})
+	t.Run("validates before registering", func(t *testing.T) {
+		container := di.NewContainer()
+		err := di.LoadManifest(container, di.Manifest{
+			Registrations: []di.ManifestRegistration{
+				{Type: "string", Constructor: "name"},
+				{Type: "string", Constructor: "missing"},
+				{Type: "string", Constructor: "sample"},
+				{Type: "string", Constructor: "name", Lifetime: "forever"},
+			},
+		}, registry)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "constructor 'missing' is not in the registry")
+		require.Contains(t, err.Error(), "constructor 'sample' returns 'di_test.SampleInterface'")
+		require.Contains(t, err.Error(), "unknown lifetime 'forever'")
+		require.Equal(t, 0, container.Count(StringType))
+	})
+	t.Run("error joiner", func(t *testing.T) {
+		var joined []error
+		custom := errors.New("custom")
+		container := di.NewContainer(di.WithErrorJoiner(func(errs []error) error {
+			joined = errs
+			return custom
+		}))
+		err := di.LoadManifest(container, di.Manifest{
+			Registrations: []di.ManifestRegistration{
+				{Type: "string", Constructor: "missing"},
+				{Type: "string", Constructor: "sample"},
+			},
+		}, registry)
+		require.Equal(t, custom, err)
+		require.Equal(t, 2, len(joined))
+	})
+}
@@ -0,0 +1,33 @@
+//go:build go1.18
+
+package di
+
+import (
+	"reflect"
+)
+
+// Optional is a constructor parameter that receives the registration of T if one exists.
+// Resolving an Optional never fails because T is not registered, HasValue is false instead.
+type Optional[T any] struct {
+	Value    T
+	HasValue bool
+}
+
+func (Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (Optional[T]) optionalOf(instance any) (any, error) {
+	o := Optional[T]{
+		HasValue: true,
+	}
+	if instance == nil {
+		return o, nil
+	}
+	value, err := cast[T](o.optionalType(), instance)
+	if err != nil {
+		return nil, err
+	}
+	o.Value = value
+	return o, nil
+}
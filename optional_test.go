@@ -0,0 +1,65 @@
+//go:build go1.18
+
+package di_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptional(t *testing.T) {
+	describe := func(dependency di.Optional[DependencyInterface]) string {
+		if !dependency.HasValue {
+			return "absent"
+		}
+		return dependency.Value.Name()
+	}
+	t.Run("present", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, NewSample("present"))
+		result, err := di.Invoke(container, describe)
+		require.NoError(t, err)
+		require.Equal(t, "present", result)
+	})
+	t.Run("absent", func(t *testing.T) {
+		container := di.NewContainer()
+		result, err := di.Invoke(container, describe)
+		require.NoError(t, err)
+		require.Equal(t, "absent", result)
+	})
+	t.Run("constructor", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(dependency di.Optional[DependencyInterface]) SampleInterface {
+			return NewSample(describe(dependency))
+		}))
+		require.NoError(t, container.Validate())
+		instance, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "absent", instance.(SampleInterface).Name())
+	})
+	t.Run("resolution error", func(t *testing.T) {
+		container := di.NewContainer()
+		expected := errors.New("failed")
+		container.RegisterError(DependencyInterfaceType, expected)
+		_, err := di.Invoke(container, describe)
+		require.ErrorIs(t, err, expected)
+	})
+	t.Run("missing dependency of registration", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) DependencyInterface {
+			return &SampleStruct{name: s.Name()}
+		}))
+		_, err := di.Invoke(container, describe)
+		require.ErrorIs(t, err, di.ErrNotExist)
+		require.ErrorContains(t, err, SampleInterfaceType.String())
+
+		require.NoError(t, container.RegisterConstructor(func(dependency di.Optional[DependencyInterface]) AggregateInterface {
+			return &AggregateStruct{names: []string{describe(dependency)}}
+		}))
+		_, err = container.Explain(AggregateInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
@@ -0,0 +1,56 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// initializer is implemented by instances that initialize after construction when the container is created WithPostConstruct
+type initializer interface {
+	Init() error
+}
+
+// starter is implemented by instances that start after construction when the container is created WithPostConstruct
+type starter interface {
+	Start(context.Context) error
+}
+
+// construct executes the static item, checks it is not zero if registered WithRequireNonZero
+// and runs post construction if the container was created WithPostConstruct.
+// The status of the background initialization of an async constructor is returned with the instance.
+func (i *containerItem) construct(c *container, parent *resolution) (any, *asyncStatus, error) {
+	data, status, err := i.executeAsync(c, parent)
+	if err != nil || i.option.shared {
+		return data, status, err
+	}
+	if i.option.requireNonZero && isZero(data) {
+		return nil, nil, fmt.Errorf("%w: '%s'", ErrZeroValue, i.option.key)
+	}
+	if !c.postConstruct {
+		return data, status, nil
+	}
+	if init, ok := data.(initializer); ok {
+		if err := init.Init(); err != nil {
+			return nil, nil, fmt.Errorf("unable to initialize '%s': %w", i.option.key, err)
+		}
+	}
+	if start, ok := data.(starter); ok {
+		ctx, ok := parent.context()
+		if !ok {
+			ctx = context.Background()
+		}
+		if err := start.Start(ctx); err != nil {
+			return nil, nil, fmt.Errorf("unable to start '%s': %w", i.option.key, err)
+		}
+	}
+	return data, status, nil
+}
+
+// isZero returns true if the instance is nil or the zero value of its type
+func isZero(instance any) bool {
+	if instance == nil {
+		return true
+	}
+	return reflect.ValueOf(instance).IsZero()
+}
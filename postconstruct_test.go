@@ -0,0 +1,106 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycle struct {
+	name    string
+	err     error
+	calls   *[]string
+	started bool
+}
+
+func (l *lifecycle) Init() error {
+	*l.calls = append(*l.calls, "init "+l.name)
+	return l.err
+}
+
+func (l *lifecycle) Start(ctx context.Context) error {
+	*l.calls = append(*l.calls, "start "+l.name)
+	l.started = true
+	return nil
+}
+
+type dependentLifecycle struct {
+	*lifecycle
+	dependency *lifecycle
+}
+
+var lifecycleType = reflect.TypeOf(&lifecycle{})
+var dependentLifecycleType = reflect.TypeOf(&dependentLifecycle{})
+
+func TestPostConstruct(t *testing.T) {
+	t.Run("init and start once", func(t *testing.T) {
+		var calls []string
+		container := di.NewContainer(di.WithPostConstruct())
+		container.RegisterDynamic(lifecycleType, func(r di.Resolver) (any, error) {
+			calls = append(calls, "construct")
+			return &lifecycle{name: "one", calls: &calls}, nil
+		})
+		for i := 0; i < 2; i++ {
+			instance, err := container.Resolve(lifecycleType)
+			require.NoError(t, err)
+			require.True(t, instance.(*lifecycle).started)
+		}
+		require.Equal(t, []string{"construct", "init one", "start one"}, calls)
+	})
+	t.Run("dependencies first", func(t *testing.T) {
+		var calls []string
+		container := di.NewContainer(di.WithPostConstruct())
+		container.RegisterDynamic(lifecycleType, func(r di.Resolver) (any, error) {
+			return &lifecycle{name: "dependency", calls: &calls}, nil
+		})
+		container.RegisterConstructor(func(dependency *lifecycle) *dependentLifecycle {
+			return &dependentLifecycle{
+				lifecycle:  &lifecycle{name: "dependent", calls: &calls},
+				dependency: dependency,
+			}
+		})
+		_, err := container.Resolve(dependentLifecycleType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"init dependency", "start dependency", "init dependent", "start dependent"}, calls)
+	})
+	t.Run("propagates error", func(t *testing.T) {
+		var calls []string
+		initErr := errors.New("init")
+		container := di.NewContainer(di.WithPostConstruct())
+		container.RegisterInstance(lifecycleType, &lifecycle{name: "one", err: initErr, calls: &calls})
+		_, err := container.Resolve(lifecycleType)
+		require.ErrorIs(t, err, initErr)
+		require.Equal(t, []string{"init one"}, calls)
+	})
+	t.Run("scope", func(t *testing.T) {
+		var calls []string
+		container := di.NewContainer(di.WithPostConstruct())
+		scope := container.Scope()
+		scope.RegisterInstance(lifecycleType, &lifecycle{name: "one", calls: &calls})
+		_, err := scope.Resolve(lifecycleType)
+		require.NoError(t, err)
+		require.Equal(t, []string{"init one", "start one"}, calls)
+	})
+	t.Run("skips per request", func(t *testing.T) {
+		var calls []string
+		container := di.NewContainer(di.WithPostConstruct())
+		container.RegisterDynamic(lifecycleType, func(r di.Resolver) (any, error) {
+			return &lifecycle{name: "one", calls: &calls}, nil
+		}, di.WithLifetime(di.LifetimePerRequest))
+		_, err := container.Resolve(lifecycleType)
+		require.NoError(t, err)
+		require.Empty(t, calls)
+	})
+	t.Run("disabled by default", func(t *testing.T) {
+		var calls []string
+		container := di.NewContainer()
+		container.RegisterInstance(lifecycleType, &lifecycle{name: "one", calls: &calls})
+		_, err := container.Resolve(lifecycleType)
+		require.NoError(t, err)
+		require.Empty(t, calls)
+	})
+}
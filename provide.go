@@ -0,0 +1,20 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Provide registers each constructor with RegisterConstructor. Every constructor is registered even if
+// another fails, the errors of the constructors that fail are joined into the returned error with the
+// container's error joiner.
+func Provide(container Container, constructors ...any) error {
+	var errs []error
+	for i, constructor := range constructors {
+		err := container.RegisterConstructor(constructor)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to provide constructor %d of type '%v': %w", i, reflect.TypeOf(constructor), err))
+		}
+	}
+	return joinResolverErrors(container, errs)
+}
@@ -0,0 +1,47 @@
+package di_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvide(t *testing.T) {
+	t.Run("registers constructors", func(t *testing.T) {
+		container := di.NewContainer()
+		err := di.Provide(container,
+			func() string { return "test" },
+			NewSample,
+			func(s SampleInterface) DependencyInterface { return s })
+		require.NoError(t, err)
+
+		instance, err := container.Resolve(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(DependencyInterface).Name())
+	})
+	t.Run("aggregates errors", func(t *testing.T) {
+		container := di.NewContainer()
+		err := di.Provide(container,
+			NewSample,
+			func() {},
+			"not a function")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "constructor 1 of type 'func()'")
+		require.Contains(t, err.Error(), "constructor 2 of type 'string'")
+		require.NotContains(t, err.Error(), "constructor 0")
+		require.Equal(t, 1, container.Count(SampleInterfaceType))
+	})
+	t.Run("error joiner", func(t *testing.T) {
+		var joined []error
+		custom := errors.New("custom")
+		container := di.NewContainer(di.WithErrorJoiner(func(errs []error) error {
+			joined = errs
+			return custom
+		}))
+		err := di.Provide(container, func() {}, "not a function")
+		require.Equal(t, custom, err)
+		require.Equal(t, 2, len(joined))
+	})
+}
@@ -0,0 +1,200 @@
+package di
+
+import (
+	"context"
+	"reflect"
+)
+
+// readOnlyContainer wraps a container and rejects any change to its registrations.
+// Methods that return an error return ErrReadOnly, methods without an error panic with ErrReadOnly.
+type readOnlyContainer struct {
+	Container
+	container *container
+}
+
+func (c *container) ReadOnly() Container {
+	return &readOnlyContainer{
+		Container: c,
+		container: c,
+	}
+}
+
+func (c *readOnlyContainer) ReadOnly() Container {
+	return c
+}
+
+// Scope returns a read only view of a new scope so the view can not be used to register into a scope
+func (c *readOnlyContainer) Scope() Container {
+	return c.container.Scope().ReadOnly()
+}
+
+// start returns the resolution that resolutions started by the Resolver methods of the view are nested in,
+// so Container fields injected while resolving receive the view instead of the container
+func (c *readOnlyContainer) start() *resolution {
+	return &resolution{
+		container: c.container,
+		origin:    c,
+	}
+}
+
+func (c *readOnlyContainer) Resolve(t reflect.Type) (any, error) {
+	instance, err := c.container.resolve(c.start(), t)
+	return instance, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveAll(t reflect.Type) ([]any, error) {
+	instances, err := c.container.resolveAll(c.start(), t)
+	return instances, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveMap(t reflect.Type) (map[string]any, error) {
+	instances, err := c.container.resolveMap(c.start(), t)
+	return instances, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveByName(t reflect.Type, name string) (any, error) {
+	instance, err := c.container.resolveByName(c.start(), t, name)
+	return instance, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveByKey(t reflect.Type, key any) (any, error) {
+	instance, err := c.container.resolveByKey(c.start(), t, key)
+	return instance, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveKeyed(t reflect.Type) (map[any]any, error) {
+	instances, err := c.container.resolveKeyed(c.start(), t)
+	return instances, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveWith(t reflect.Type, overrides ...any) (any, error) {
+	instance, err := c.withOverrides(overrides).Resolve(t)
+	return instance, c.container.check(err)
+}
+
+func (c *readOnlyContainer) ResolveWithType(t reflect.Type) (any, reflect.Type, error) {
+	instance, err := c.Resolve(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance == nil {
+		return nil, t, nil
+	}
+	return instance, reflect.TypeOf(instance), nil
+}
+
+func (c *readOnlyContainer) withCallCache() Resolver {
+	r := c.start()
+	r.cache = newCallCache()
+	return r
+}
+
+func (c *readOnlyContainer) registered(t reflect.Type) bool {
+	return c.container.registered(t)
+}
+
+func (c *readOnlyContainer) registeredName(t reflect.Type, name string) bool {
+	return c.container.registeredName(t, name)
+}
+
+func (c *readOnlyContainer) resolveRest(t reflect.Type) ([]any, error) {
+	instances, err := c.container.resolveRestFrom(c.start(), t)
+	return instances, c.container.check(err)
+}
+
+func (c *readOnlyContainer) context() (context.Context, bool) {
+	if r, ok := c.Container.(contextResolver); ok {
+		return r.context()
+	}
+	return nil, false
+}
+
+func (c *readOnlyContainer) withOverrides(overrides []any) Resolver {
+	r := c.start()
+	r.overrides = overrides
+	return r
+}
+
+func (c *readOnlyContainer) RegisterInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) RegisterNamedInstances(t reflect.Type, instances map[string]any, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) OnResolve(hook func(t reflect.Type, instance any) (any, error)) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) RegisterDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) RegisterConstructor(constructor any, options ...InstanceRegistrationOption) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) RegisterConstructorAs(constructor any, types []reflect.Type, options ...InstanceRegistrationOption) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) RegisterStruct(t reflect.Type, options ...InstanceRegistrationOption) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) RegisterError(t reflect.Type, err error, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) AppendInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) Handle {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) Remove(handle Handle) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) RegisterSpec(spec RegistrationSpec) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) RegisterNamedType(typeName string, t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) RegisterAlias(alias reflect.Type, target reflect.Type, options ...InstanceRegistrationOption) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyContainer) ReplaceDynamic(t reflect.Type, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) ReplaceInstance(t reflect.Type, instance any, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) ReplaceByName(t reflect.Type, name string, instance any, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) ReplaceDynamicByName(t reflect.Type, name string, delegate FuncResolver, options ...InstanceRegistrationOption) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) SetLifetime(t reflect.Type, lifetime Lifetime) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) ClearCache() {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) RemoveAll(t reflect.Type) {
+	panic(ErrReadOnly)
+}
+
+func (c *readOnlyContainer) Close() error {
+	return ErrReadOnly
+}
@@ -0,0 +1,105 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	t.Run("resolves", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample))
+
+		readOnly := container.ReadOnly()
+		instance, err := readOnly.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance.(SampleInterface).Name())
+
+		result, err := di.Invoke(readOnly, func(s SampleInterface) string {
+			return s.Name()
+		})
+		require.NoError(t, err)
+		require.Equal(t, "test", result)
+	})
+	t.Run("rejects registration", func(t *testing.T) {
+		readOnly := di.NewContainer().ReadOnly()
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.RegisterInstance(StringType, "test")
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.RemoveAll(StringType)
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.ReplaceByName(StringType, "name", "test")
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.RegisterNamedInstances(StringType, map[string]any{"name": "test"})
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.RegisterNamedType("name", StringType, func(r di.Resolver) (any, error) {
+				return "test", nil
+			})
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.OnResolve(func(t reflect.Type, instance any) (any, error) {
+				return instance, nil
+			})
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.SetLifetime(StringType, di.LifetimeStatic)
+		})
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			readOnly.ClearCache()
+		})
+		require.ErrorIs(t, readOnly.RegisterConstructor(NewSample), di.ErrReadOnly)
+		require.ErrorIs(t, readOnly.RegisterConstructorAs(NewSample, []reflect.Type{SampleInterfaceType}), di.ErrReadOnly)
+		require.ErrorIs(t, readOnly.RegisterSpec(di.RegistrationSpec{Type: StringType, Instance: "test"}), di.ErrReadOnly)
+		require.ErrorIs(t, readOnly.RegisterAlias(DependencyInterfaceType, SampleInterfaceType), di.ErrReadOnly)
+		require.ErrorIs(t, readOnly.Close(), di.ErrReadOnly)
+	})
+	t.Run("scope is read only", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+
+		scope := container.ReadOnly().Scope()
+		require.PanicsWithValue(t, di.ErrReadOnly, func() {
+			scope.RegisterInstance(CloserType, &closer{name: "plugin"})
+		})
+		instance, err := scope.Resolve(StringType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance)
+	})
+	t.Run("invoke parity", func(t *testing.T) {
+		container := di.NewContainer()
+		for _, name := range []string{"one", "two"} {
+			container.RegisterInstance(DependencyInterfaceType, NewSample(name))
+		}
+		names := func(first DependencyInterface, rest ...DependencyInterface) []string {
+			names := []string{first.Name()}
+			for _, d := range rest {
+				names = append(names, d.Name())
+			}
+			return names
+		}
+		expected, err := di.Invoke(container, names)
+		require.NoError(t, err)
+		require.Equal(t, []string{"one", "two"}, expected)
+
+		actual, err := di.Invoke(container.ReadOnly(), names)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+	t.Run("sees later registrations", func(t *testing.T) {
+		container := di.NewContainer()
+		readOnly := container.ReadOnly()
+		container.RegisterInstance(StringType, "test")
+
+		instance, err := readOnly.Resolve(StringType)
+		require.NoError(t, err)
+		require.Equal(t, "test", instance)
+	})
+}
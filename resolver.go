@@ -1,13 +1,16 @@
 package di
 
 import (
+	"context"
 	"reflect"
+	"sync"
 )
 
 // Resolver resolves an instance from a given type
 type Resolver interface {
 
-	// Resolve resolves the instace registered for a given type
+	// Resolve resolves the instace registered for a given type. A typed nil returned by a registration is returned as is,
+	// so the result is not equal to nil. A type that is not registered returns an error wrapping ErrNotExist.
 	Resolve(t reflect.Type) (any, error)
 
 	// ResolveAll resolves all instances registered for the given type
@@ -18,4 +21,256 @@ type Resolver interface {
 
 	// ResolveByName resolves the instance registered for a given type and name
 	ResolveByName(t reflect.Type, name string) (any, error)
+
+	// ResolveByKey resolves the instance registered for a given type and key
+	ResolveByKey(t reflect.Type, key any) (any, error)
+
+	// ResolveKeyed resolves all keyed instances as a map by their key
+	ResolveKeyed(t reflect.Type) (map[any]any, error)
+}
+
+// resolution is the Resolver handed to resolvers while an item is being resolved.
+// It links back to the resolution that requested it so re-entrant requests can be detected.
+type resolution struct {
+	container *container
+	parent    *resolution
+	item      *containerItem
+	autowired reflect.Type
+
+	// ctx is the context of the resolution, inherited by nested resolutions
+	ctx context.Context
+
+	// overrides are instances supplied at resolve time that take precedence over registrations
+	overrides []any
+
+	// cache holds the per request instances of the call when Invoke is called WithSharedPerCallCache
+	cache *callCache
+
+	// trace records the items resolved under this resolution when resolving with ResolveTrace
+	trace *traceNode
+
+	// origin is the container the resolution was started from, injected into Container fields
+	origin Container
+
+	// async is the status of the background initialization reported by the async constructor of the item
+	async *asyncStatus
+
+	// asyncResult receives the status of the instance of the item resolved directly under the resolution, see ResolveAsync
+	asyncResult **asyncStatus
+}
+
+// callCache holds the per request instances constructed during a single call
+type callCache struct {
+	mutex     sync.Mutex
+	instances map[*containerItem]*scopedInstance
+}
+
+// instance returns the cached instance of the item, creating an empty one the first time
+func (cache *callCache) instance(item *containerItem) *scopedInstance {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	instance, ok := cache.instances[item]
+	if !ok {
+		instance = &scopedInstance{}
+		cache.instances[item] = instance
+	}
+	return instance
+}
+
+// callCacheResolver returns a resolver that shares per request instances for the rest of the call
+type callCacheResolver interface {
+	withCallCache() Resolver
+}
+
+func newCallCache() *callCache {
+	return &callCache{
+		instances: map[*containerItem]*scopedInstance{},
+	}
+}
+
+func (c *container) withCallCache() Resolver {
+	return &resolution{
+		container: c,
+		cache:     newCallCache(),
+		origin:    c,
+	}
+}
+
+func (r *resolution) withCallCache() Resolver {
+	return &resolution{
+		container: r.container,
+		parent:    r,
+		cache:     newCallCache(),
+	}
+}
+
+// overridesResolver returns a resolver that uses the overrides before the registrations
+type overridesResolver interface {
+	withOverrides(overrides []any) Resolver
+}
+
+func (c *container) withOverrides(overrides []any) Resolver {
+	return &resolution{
+		container: c,
+		overrides: overrides,
+		origin:    c,
+	}
+}
+
+func (r *resolution) withOverrides(overrides []any) Resolver {
+	return &resolution{
+		container: r.container,
+		parent:    r,
+		overrides: overrides,
+	}
+}
+
+// callCache returns the nearest call cache in the resolution chain
+func (r *resolution) callCache() *callCache {
+	for current := r; current != nil; current = current.parent {
+		if current.cache != nil {
+			return current.cache
+		}
+	}
+	return nil
+}
+
+// overrideResolver provides the instances supplied with ResolveWith
+type overrideResolver interface {
+	override(t reflect.Type) (any, bool)
+}
+
+// override returns the first supplied instance whose type is t, or failing that, is assignable to t
+func (r *resolution) override(t reflect.Type) (any, bool) {
+	for current := r; current != nil; current = current.parent {
+		for _, o := range current.overrides {
+			if reflect.TypeOf(o) == t {
+				return o, true
+			}
+		}
+	}
+	for current := r; current != nil; current = current.parent {
+		for _, o := range current.overrides {
+			if o != nil && reflect.TypeOf(o).AssignableTo(t) {
+				return o, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// contextResolver provides the context of a resolution to parameters of type context.Context
+type contextResolver interface {
+	context() (context.Context, bool)
+}
+
+func (r *resolution) context() (context.Context, bool) {
+	for current := r; current != nil; current = current.parent {
+		if current.ctx != nil {
+			return current.ctx, true
+		}
+	}
+	return nil, false
+}
+
+// originContainer returns the container the resolution was started from. If it is not known, for example because
+// the resolution was started from the read only view, a read only view of the container is returned instead.
+func (r *resolution) originContainer() Container {
+	for current := r; current != nil; current = current.parent {
+		if current.origin != nil {
+			return current.origin
+		}
+	}
+	return r.container.ReadOnly()
+}
+
+// resolving returns true if the item is in progress anywhere in the resolution chain
+func (r *resolution) resolving(item *containerItem) bool {
+	for current := r; current != nil; current = current.parent {
+		if current.item == item {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns the number of items being resolved in the resolution chain
+func (r *resolution) depth() int {
+	depth := 0
+	for current := r; current != nil; current = current.parent {
+		if current.item != nil {
+			depth++
+		}
+	}
+	return depth
+}
+
+// path returns the keys of the items being resolved in the resolution chain from the outermost to the innermost
+func (r *resolution) path() []string {
+	var keys []string
+	for current := r; current != nil; current = current.parent {
+		if current.item != nil {
+			keys = append([]string{current.item.option.key}, keys...)
+		}
+	}
+	return keys
+}
+
+// static returns the static item being constructed in the resolution chain, nil if there is none
+func (r *resolution) static() *containerItem {
+	for current := r; current != nil; current = current.parent {
+		if current.item == nil {
+			continue
+		}
+		if current.item.strategy() == StaticCacheStrategy {
+			return current.item
+		}
+	}
+	return nil
+}
+
+// autowiring returns true if the type is being autowired anywhere in the resolution chain
+func (r *resolution) autowiring(t reflect.Type) bool {
+	for current := r; current != nil; current = current.parent {
+		if current.autowired == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *resolution) Resolve(t reflect.Type) (any, error) {
+	return r.container.resolve(r, t)
+}
+
+func (r *resolution) ResolveAll(t reflect.Type) ([]any, error) {
+	return r.container.resolveAll(r, t)
+}
+
+func (r *resolution) resolveRest(t reflect.Type) ([]any, error) {
+	return r.container.resolveRestFrom(r, t)
+}
+
+func (r *resolution) ResolveMap(t reflect.Type) (map[string]any, error) {
+	return r.container.resolveMap(r, t)
+}
+
+func (r *resolution) ResolveByName(t reflect.Type, name string) (any, error) {
+	return r.container.resolveByName(r, t, name)
+}
+
+func (r *resolution) ResolveByKey(t reflect.Type, key any) (any, error) {
+	return r.container.resolveByKey(r, t, key)
+}
+
+func (r *resolution) registered(t reflect.Type) bool {
+	return r.container.registered(t)
+}
+
+func (r *resolution) registeredName(t reflect.Type, name string) bool {
+	return r.container.registeredName(t, name)
+}
+
+func (r *resolution) ResolveKeyed(t reflect.Type) (map[any]any, error) {
+	return r.container.resolveKeyed(r, t)
 }
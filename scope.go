@@ -0,0 +1,70 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// scopedInstance caches the instance of a scoped item in a single scope
+type scopedInstance struct {
+	data  any
+	err   error
+	async *asyncStatus
+	once  sync.Once
+}
+
+func (c *container) Scope() Container {
+	return &container{
+		groups:               map[reflect.Type]*containerItemGroup{},
+		defaultOptions:       c.defaultOptions,
+		typeDefaults:         c.typeDefaults,
+		errorJoiner:          c.errorJoiner,
+		autowire:             c.autowire,
+		dedup:                c.dedup,
+		assignable:           c.assignable,
+		namePrefix:           c.namePrefix,
+		withoutNamedFallback: c.withoutNamedFallback,
+		sliceOrderByName:     c.sliceOrderByName,
+		mapKeyMetadata:       c.mapKeyMetadata,
+		panicOnResolveError:  c.panicOnResolveError,
+		disposeOrder:         c.disposeOrder,
+		postConstruct:        c.postConstruct,
+		maxResolveDepth:      c.maxResolveDepth,
+		sliceOptions:         c.sliceOptions,
+		parent:               c,
+	}
+}
+
+// root returns the container at the top of the scope chain
+func (c *container) root() *container {
+	for c.parent != nil {
+		c = c.parent
+	}
+	return c
+}
+
+// resolveScoped resolves the item once in this scope and reports if the result came from the scope's cache.
+// The instance is closed when the scope is closed.
+func (c *container) resolveScoped(i *containerItem, parent *resolution) (any, bool, error) {
+	c.mutex.Lock()
+	if c.scoped == nil {
+		c.scoped = map[*containerItem]*scopedInstance{}
+	}
+	instance, ok := c.scoped[i]
+	if !ok {
+		instance = &scopedInstance{}
+		c.scoped[i] = instance
+	}
+	c.mutex.Unlock()
+
+	executed := false
+	instance.once.Do(func() {
+		executed = true
+		instance.data, instance.async, instance.err = i.executeAsync(c, parent)
+		if instance.err == nil {
+			c.track(i, instance.data)
+		}
+	})
+	parent.reportAsync(instance.async)
+	return instance.data, !executed, instance.err
+}
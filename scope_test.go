@@ -0,0 +1,173 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	newContainer := func() di.Container {
+		container := di.NewContainer()
+		container.RegisterDynamic(SampleInterfaceType, func(r di.Resolver) (any, error) {
+			return NewSample("scoped"), nil
+		}, di.WithLifetime(di.LifetimeScoped))
+		container.RegisterDynamic(DependencyInterfaceType, func(r di.Resolver) (any, error) {
+			return &SampleStruct{name: "static"}, nil
+		}, di.WithLifetime(di.LifetimeStatic))
+		return container
+	}
+	t.Run("scoped shared within scope", func(t *testing.T) {
+		scope := newContainer().Scope()
+		first, err := scope.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		second, err := scope.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, first, second)
+	})
+	t.Run("scoped differs between sibling scopes", func(t *testing.T) {
+		container := newContainer()
+		left, err := container.Scope().Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		right, err := container.Scope().Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.NotSame(t, left, right)
+	})
+	t.Run("nested scopes", func(t *testing.T) {
+		container := newContainer()
+		outer := container.Scope()
+		inner := outer.Scope()
+		sibling := outer.Scope()
+
+		outerInstance, err := outer.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		innerInstance, err := inner.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		innerAgain, err := inner.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		siblingInstance, err := sibling.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+
+		require.Same(t, innerInstance, innerAgain)
+		require.NotSame(t, outerInstance, innerInstance)
+		require.NotSame(t, innerInstance, siblingInstance)
+	})
+	t.Run("static shared globally", func(t *testing.T) {
+		container := newContainer()
+		outer := container.Scope()
+		inner := outer.Scope()
+		fromRoot, err := container.Resolve(DependencyInterfaceType)
+		require.NoError(t, err)
+		fromOuter, err := outer.Resolve(DependencyInterfaceType)
+		require.NoError(t, err)
+		fromInner, err := inner.Resolve(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, fromRoot, fromOuter)
+		require.Same(t, fromRoot, fromInner)
+	})
+	t.Run("static resolves dependencies from its container", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(DependencyInterfaceType, &SampleStruct{name: "root"})
+		require.NoError(t, container.RegisterConstructor(func(d DependencyInterface) SampleInterface {
+			return NewSample(d.Name())
+		}))
+		scope := container.Scope()
+		scope.RegisterInstance(DependencyInterfaceType, &SampleStruct{name: "scope"})
+
+		fromScope, err := scope.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "root", fromScope.(SampleInterface).Name())
+
+		fromRoot, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Same(t, fromScope, fromRoot)
+	})
+	t.Run("static can not depend on scoped", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterDynamic(DependencyInterfaceType, func(r di.Resolver) (any, error) {
+			return &SampleStruct{name: "scoped"}, nil
+		}, di.WithLifetime(di.LifetimeScoped))
+		require.NoError(t, container.RegisterConstructor(func(d DependencyInterface) SampleInterface {
+			return NewSample(d.Name())
+		}))
+		scope := container.Scope()
+
+		_, err := scope.Resolve(SampleInterfaceType)
+		require.ErrorContains(t, err, "can not depend on scoped")
+		_, err = container.Resolve(SampleInterfaceType)
+		require.ErrorContains(t, err, "can not depend on scoped")
+
+		// a per request item may still depend on a scoped item
+		require.NoError(t, container.RegisterConstructor(func(d DependencyInterface) SampleInterface {
+			return NewSample(d.Name())
+		}, di.WithName("per request"), di.WithLifetime(di.LifetimePerRequest)))
+		instance, err := scope.ResolveByName(SampleInterfaceType, "per request")
+		require.NoError(t, err)
+		require.Equal(t, "scoped", instance.(SampleInterface).Name())
+	})
+	t.Run("scope overrides parent", func(t *testing.T) {
+		container := newContainer()
+		scope := container.Scope()
+		scope.RegisterInstance(SampleInterfaceType, NewSample("override"))
+
+		instance, err := scope.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "override", instance.(SampleInterface).Name())
+
+		instance, err = container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "scoped", instance.(SampleInterface).Name())
+	})
+	t.Run("types include parent", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "parent")
+		scope := container.Scope()
+		scope.RegisterInstance(StringType, "scope")
+		scope.RegisterInstance(SampleInterfaceType, NewSample("scope"))
+
+		require.Equal(t, []reflect.Type{SampleInterfaceType, StringType}, scope.Types())
+		require.Equal(t, []reflect.Type{StringType}, container.Types())
+	})
+	t.Run("close scope", func(t *testing.T) {
+		var closed []string
+		container := di.NewContainer()
+		container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+			return &closer{name: "scoped", closed: &closed}, nil
+		}, di.WithLifetime(di.LifetimeScoped))
+		scope := container.Scope()
+		_, err := scope.Resolve(CloserType)
+		require.NoError(t, err)
+
+		require.NoError(t, container.Close())
+		require.Empty(t, closed)
+		require.NoError(t, scope.Close())
+		require.Equal(t, []string{"scoped"}, closed)
+	})
+	t.Run("close scope dispose order", func(t *testing.T) {
+		var closed []string
+		container := di.NewContainer(di.WithDisposeOrder(di.DisposeFIFO))
+		for _, name := range []string{"one", "two"} {
+			name := name
+			container.RegisterDynamic(CloserType, func(r di.Resolver) (any, error) {
+				return &closer{name: name, closed: &closed}, nil
+			}, di.WithName(name), di.WithLifetime(di.LifetimeScoped))
+		}
+		scope := container.Scope()
+		for _, name := range []string{"one", "two"} {
+			_, err := scope.ResolveByName(CloserType, name)
+			require.NoError(t, err)
+		}
+		require.NoError(t, scope.Close())
+		require.Equal(t, []string{"one", "two"}, closed)
+	})
+	t.Run("type defaults", func(t *testing.T) {
+		container := di.NewContainer(di.WithTypeDefaults(SampleInterfaceType, di.WithLifetime(di.LifetimePerRequest)))
+		scope := container.Scope()
+		scope.RegisterInstance(SampleInterfaceType, NewSample("scoped"))
+		registrations := scope.Registrations(SampleInterfaceType)
+		require.Equal(t, 1, len(registrations))
+		require.Equal(t, di.LifetimePerRequest, registrations[0].Lifetime)
+	})
+}
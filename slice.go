@@ -0,0 +1,60 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SliceOptions selects the registrations resolved into a slice by ResolveAll and slice parameters
+type SliceOptions struct {
+	// Exact includes the unnamed registrations of the element type in registration order
+	Exact bool
+
+	// Named includes the named registrations of the element type sorted by name followed by its keyed registrations
+	Named bool
+
+	// Assignable includes the registrations of other types that are assignable to the element type in registration order
+	Assignable bool
+
+	// Dedup removes repeated pointer like instances, keeping the first occurrence
+	Dedup bool
+}
+
+// WithSliceOptions changes which registrations are resolved into a slice. Without it a slice holds every registration
+// of the element type.
+func WithSliceOptions(options SliceOptions) ContainerOption {
+	return containerOption(func(c *container) {
+		c.sliceOptions = &options
+	})
+}
+
+// sliceItems returns the items selected by the slice options for the element type t
+func (c *container) sliceItems(t reflect.Type) ([]*containerItem, error) {
+	options := c.sliceOptions
+	var items []*containerItem
+	group, err := c.group(t)
+	if err == nil {
+		if options.Exact {
+			items = append(items, group.items...)
+		}
+		if options.Named {
+			for _, name := range group.names() {
+				items = append(items, group.namedItems[name])
+			}
+			for _, key := range group.keys {
+				items = append(items, group.keyedItems[key])
+			}
+		}
+	}
+	if options.Assignable {
+		for _, item := range c.assignableItems(t) {
+			if item.option.typ != t {
+				items = append(items, item)
+			}
+		}
+	}
+	if len(items) == 0 && err != nil {
+		return nil, fmt.Errorf("%w: no registration of '%s' is selected by the slice options", ErrNotExist, t)
+	}
+	return items, nil
+}
@@ -0,0 +1,72 @@
+package di_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceOptions(t *testing.T) {
+	shared := &SampleStruct{name: "shared"}
+	register := func(container di.Container) {
+		container.RegisterInstance(SampleInterfaceType, NewSample("exact"))
+		container.RegisterInstance(SampleInterfaceType, shared)
+		container.RegisterInstance(SampleInterfaceType, NewSample("named"), di.WithName("named"))
+		container.RegisterInstance(reflect.TypeOf(&SampleStruct{}), shared)
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "assignable"}})
+	}
+	names := func(t *testing.T, container di.Container) []string {
+		var names []string
+		_, err := di.Invoke(container, func(samples []SampleInterface) {
+			for _, sample := range samples {
+				names = append(names, sample.Name())
+			}
+		})
+		require.NoError(t, err)
+		return names
+	}
+
+	type test struct {
+		name     string
+		options  di.SliceOptions
+		expected []string
+	}
+	tests := []test{
+		{"exact", di.SliceOptions{Exact: true}, []string{"exact", "shared"}},
+		{"exact and named", di.SliceOptions{Exact: true, Named: true}, []string{"exact", "shared", "named"}},
+		{"exact and assignable", di.SliceOptions{Exact: true, Assignable: true}, []string{"exact", "shared", "shared", "assignable"}},
+		{"dedup", di.SliceOptions{Exact: true, Named: true, Assignable: true, Dedup: true}, []string{"exact", "shared", "named", "assignable"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			container := di.NewContainer(di.WithSliceOptions(test.options))
+			register(container)
+			require.Equal(t, test.expected, names(t, container))
+
+			// every ResolveAll variant selects the same registrations
+			all, err := container.ResolveAllContext(context.Background(), SampleInterfaceType)
+			require.NoError(t, err)
+			var streamed []any
+			for result := range container.ResolveAllChan(context.Background(), SampleInterfaceType) {
+				require.NoError(t, result.Err)
+				streamed = append(streamed, result.Value)
+			}
+			require.Equal(t, len(test.expected), len(all))
+			require.Equal(t, all, streamed)
+		})
+	}
+	t.Run("assignable only", func(t *testing.T) {
+		container := di.NewContainer(di.WithSliceOptions(di.SliceOptions{Assignable: true}))
+		container.RegisterInstance(EmbeddedType, Embedded{Named: Named{name: "assignable"}})
+		require.Equal(t, []string{"assignable"}, names(t, container))
+	})
+	t.Run("none selected", func(t *testing.T) {
+		container := di.NewContainer(di.WithSliceOptions(di.SliceOptions{Exact: true}))
+		_, err := container.ResolveAll(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
@@ -0,0 +1,68 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Stats are the resolution metrics of a container
+type Stats struct {
+	// Resolutions is the number of registrations resolved, including nested dependencies
+	Resolutions uint64
+
+	// CacheHits is the number of resolutions returned from a static or scoped cache
+	CacheHits uint64
+
+	// MaxDepth is the deepest dependency chain resolved, where a registration without dependencies has a depth of one
+	MaxDepth int
+
+	// Types is the number of resolutions of each registered type
+	Types map[reflect.Type]uint64
+}
+
+// WithStats collects resolution metrics that are returned by Stats. Metrics are not collected by default to avoid the overhead.
+func WithStats() ContainerOption {
+	return containerOption(func(c *container) {
+		c.stats = &statsCollector{
+			stats: Stats{
+				Types: map[reflect.Type]uint64{},
+			},
+		}
+	})
+}
+
+// statsCollector updates the stats of a container during resolution
+type statsCollector struct {
+	mutex sync.Mutex
+	stats Stats
+}
+
+func (s *statsCollector) record(t reflect.Type, depth int, cached bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.stats.Resolutions++
+	if cached {
+		s.stats.CacheHits++
+	}
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+	s.stats.Types[t]++
+}
+
+func (c *container) Stats() Stats {
+	stats := c.root().stats
+	if stats == nil {
+		return Stats{
+			Types: map[reflect.Type]uint64{},
+		}
+	}
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	result := stats.stats
+	result.Types = map[reflect.Type]uint64{}
+	for t, count := range stats.stats.Types {
+		result.Types[t] = count
+	}
+	return result
+}
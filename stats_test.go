@@ -0,0 +1,44 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("depth and counts", func(t *testing.T) {
+		container := di.NewContainer(di.WithStats(), di.WithDefaultLifetime(di.LifetimePerRequest))
+		container.RegisterInstance(StringType, "test", di.WithLifetime(di.LifetimeStatic))
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) DependencyInterface {
+			return s
+		}))
+		require.NoError(t, container.RegisterConstructor(func(d DependencyInterface) AggregateInterface {
+			return NewAggregate([]DependencyInterface{d})
+		}))
+
+		for i := 0; i < 2; i++ {
+			_, err := container.Resolve(AggregateInterfaceType)
+			require.NoError(t, err)
+		}
+
+		stats := container.Stats()
+		require.Equal(t, 4, stats.MaxDepth)
+		require.Equal(t, uint64(8), stats.Resolutions)
+		require.Equal(t, uint64(1), stats.CacheHits)
+		require.Equal(t, uint64(2), stats.Types[AggregateInterfaceType])
+		require.Equal(t, uint64(2), stats.Types[StringType])
+	})
+	t.Run("disabled", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		_, err := container.Resolve(StringType)
+		require.NoError(t, err)
+
+		stats := container.Stats()
+		require.Equal(t, uint64(0), stats.Resolutions)
+		require.Empty(t, stats.Types)
+	})
+}
@@ -0,0 +1,88 @@
+package di
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Trace describes how a type was actually resolved by the container
+type Trace struct {
+	// Type is the registered type of the registration that was used
+	Type reflect.Type
+
+	// Name is the name of the registration, empty if unnamed
+	Name string
+
+	// Lifetime is the lifetime of the registration
+	Lifetime Lifetime
+
+	// Metadata is the metadata of the registration
+	Metadata map[string]any
+
+	// Cached is true if the instance was returned from a cache instead of being constructed
+	Cached bool
+
+	// Dependencies are the traces of the registrations resolved while constructing the instance, in the order
+	// they were resolved. A cached instance has no dependencies.
+	Dependencies []Trace
+}
+
+// traceNode records the resolution of an item and the items resolved while constructing it
+type traceNode struct {
+	item         *containerItem
+	cached       bool
+	mutex        sync.Mutex
+	dependencies []*traceNode
+}
+
+func (n *traceNode) add(child *traceNode) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.dependencies = append(n.dependencies, child)
+}
+
+func (n *traceNode) build() Trace {
+	info := n.item.info()
+	trace := Trace{
+		Type:         info.Type,
+		Name:         info.Name,
+		Lifetime:     info.Lifetime,
+		Metadata:     info.Metadata,
+		Cached:       n.cached,
+		Dependencies: []Trace{},
+	}
+	for _, dependency := range n.dependencies {
+		trace.Dependencies = append(trace.Dependencies, dependency.build())
+	}
+	return trace
+}
+
+// traceItem records the resolution of the item under the nearest trace in the resolution chain and returns
+// the resolution that nested resolutions of the item use. It returns the parent unchanged if nothing is traced.
+func (r *resolution) traceItem(c *container, item *containerItem) (*resolution, *traceNode) {
+	for current := r; current != nil; current = current.parent {
+		if current.trace == nil {
+			continue
+		}
+		child := &traceNode{item: item}
+		current.trace.add(child)
+		return &resolution{container: c, parent: r, trace: child}, child
+	}
+	return r, nil
+}
+
+func (c *container) ResolveTrace(t reflect.Type) (any, Trace, error) {
+	root := &traceNode{}
+	parent := &resolution{
+		container: c,
+		trace:     root,
+	}
+	instance, err := c.resolve(parent, t)
+	if err != nil {
+		return nil, Trace{}, c.check(err)
+	}
+	if len(root.dependencies) == 0 {
+		return instance, Trace{Type: t, Dependencies: []Trace{}}, nil
+	}
+	return instance, root.dependencies[0].build(), nil
+}
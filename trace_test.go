@@ -0,0 +1,58 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTrace(t *testing.T) {
+	t.Run("dependencies", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "beta", di.WithName("beta"))
+		container.RegisterInstance(StringType, "alpha", di.WithName("alpha"), di.WithMetadata("source", "test"))
+		require.NoError(t, container.RegisterConstructor(NewSample, di.WithLifetime(di.LifetimePerRequest)))
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface, name string) DependencyInterface {
+			return s
+		}, di.WithLifetime(di.LifetimeStatic)))
+
+		instance, trace, err := container.ResolveTrace(DependencyInterfaceType)
+		require.NoError(t, err)
+		require.Equal(t, "alpha", instance.(DependencyInterface).Name())
+
+		require.Equal(t, DependencyInterfaceType, trace.Type)
+		require.Equal(t, di.LifetimeStatic, trace.Lifetime)
+		require.False(t, trace.Cached)
+		require.Equal(t, 2, len(trace.Dependencies))
+
+		sample := trace.Dependencies[0]
+		require.Equal(t, SampleInterfaceType, sample.Type)
+		require.Equal(t, di.LifetimePerRequest, sample.Lifetime)
+		require.Equal(t, 1, len(sample.Dependencies))
+		require.Equal(t, "alpha", sample.Dependencies[0].Name)
+		require.Equal(t, "test", sample.Dependencies[0].Metadata["source"])
+
+		name := trace.Dependencies[1]
+		require.Equal(t, StringType, name.Type)
+		require.Equal(t, "alpha", name.Name)
+		require.True(t, name.Cached)
+	})
+	t.Run("cached", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		_, err := container.Resolve(SampleInterfaceType)
+		require.NoError(t, err)
+
+		_, trace, err := container.ResolveTrace(SampleInterfaceType)
+		require.NoError(t, err)
+		require.True(t, trace.Cached)
+		require.Empty(t, trace.Dependencies)
+	})
+	t.Run("missing", func(t *testing.T) {
+		container := di.NewContainer()
+		_, _, err := container.ResolveTrace(SampleInterfaceType)
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+}
@@ -0,0 +1,79 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (c *container) Validate() error {
+	var errs []error
+	for _, group := range c.groups {
+		for _, item := range group.ordered() {
+			err := c.validateItem(item)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return c.joinErrors(errs)
+}
+
+func (c *container) validateItem(item *containerItem) error {
+	// explaining the item walks every dependency without running any resolvers
+	_, err := c.explainItem(item.option.typ, item, map[*containerItem]struct{}{})
+	if err != nil {
+		return fmt.Errorf("invalid registration for '%s': %w", item.option.key, err)
+	}
+	return nil
+}
+
+func (c *container) FindDuplicates() map[reflect.Type][]string {
+	duplicates := map[reflect.Type][]string{}
+	for t, group := range c.groups {
+		seen := map[identity][]*containerItem{}
+		var order []identity
+		for _, item := range group.ordered() {
+			key, ok := instanceIdentity(item)
+			if !ok {
+				continue
+			}
+			if _, ok := seen[key]; !ok {
+				order = append(order, key)
+			}
+			seen[key] = append(seen[key], item)
+		}
+		for _, key := range order {
+			if len(seen[key]) < 2 {
+				continue
+			}
+			for _, item := range seen[key] {
+				duplicates[t] = append(duplicates[t], item.describe())
+			}
+		}
+	}
+	return duplicates
+}
+
+// instanceIdentity returns the identity of the pointer like instance registered for the item
+func instanceIdentity(item *containerItem) (identity, bool) {
+	if !item.option.isInstance || item.option.instance == nil {
+		return identity{}, false
+	}
+	v := reflect.ValueOf(item.option.instance)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return identity{t: v.Type(), pointer: v.Pointer()}, true
+	}
+	return identity{}, false
+}
+
+// describe identifies the registration by its name, its key or, if it has neither, its registration index
+func (i *containerItem) describe() string {
+	switch {
+	case i.option.itemKey != nil:
+		return fmt.Sprintf("key '%v'", i.option.itemKey)
+	case i.option.name != "":
+		return fmt.Sprintf("name '%s'", i.option.name)
+	}
+	return fmt.Sprintf("index %d", i.option.index)
+}
@@ -0,0 +1,77 @@
+package di_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/patrickhuber/go-di"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(StringType, "test")
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NoError(t, container.Validate())
+	})
+	t.Run("missing dependency", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		err := container.Validate()
+		require.ErrorIs(t, err, di.ErrNotExist)
+	})
+	t.Run("circular dependency", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NoError(t, container.RegisterConstructor(func(s SampleInterface) string {
+			return s.Name()
+		}))
+		err := container.Validate()
+		require.ErrorIs(t, err, di.ErrCircular)
+	})
+	t.Run("optional collections", func(t *testing.T) {
+		container := di.NewContainer()
+		require.NoError(t, container.RegisterConstructor(NewAggregate, di.WithOptionalCollections()))
+		require.NoError(t, container.Validate())
+
+		_, err := container.Resolve(AggregateInterfaceType)
+		require.NoError(t, err)
+	})
+	t.Run("custom joiner", func(t *testing.T) {
+		custom := errors.New("custom")
+		var joined []error
+		container := di.NewContainer(di.WithErrorJoiner(func(errs []error) error {
+			joined = errs
+			return custom
+		}))
+		require.NoError(t, container.RegisterConstructor(NewSample))
+		require.NoError(t, container.RegisterConstructor(NewExplained))
+
+		err := container.Validate()
+		require.Equal(t, custom, err)
+		require.Equal(t, 2, len(joined))
+	})
+}
+
+func TestFindDuplicates(t *testing.T) {
+	t.Run("reports duplicates", func(t *testing.T) {
+		container := di.NewContainer()
+		sample := NewSample("shared")
+		container.RegisterInstance(SampleInterfaceType, sample)
+		container.RegisterInstance(SampleInterfaceType, NewSample("other"))
+		container.RegisterInstance(SampleInterfaceType, sample, di.WithName("copy"))
+		container.RegisterInstance(StringType, "value")
+		container.RegisterInstance(StringType, "value")
+
+		duplicates := container.FindDuplicates()
+		require.Equal(t, 1, len(duplicates))
+		require.Equal(t, []string{"index 1", "name 'copy'"}, duplicates[SampleInterfaceType])
+	})
+	t.Run("none", func(t *testing.T) {
+		container := di.NewContainer()
+		container.RegisterInstance(SampleInterfaceType, NewSample("one"))
+		container.RegisterInstance(SampleInterfaceType, NewSample("two"))
+		require.Empty(t, container.FindDuplicates())
+	})
+}